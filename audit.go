@@ -0,0 +1,42 @@
+package gdprclient
+
+import "time"
+
+// AuditEvent records one successful mutating call
+type AuditEvent struct {
+	Operation    string    // Controller action, e.g. "create" or "delete/update"
+	PartitionKey string    // Partition key of the request that was changed
+	RangeKey     string    // Range key of the request that was changed
+	Time         time.Time // When the service confirmed the change
+}
+
+// AuditSink receives an AuditEvent after every successful create, update or delete made through
+// the client. Failed calls are never reported, so the events form a record of changes the service
+// accepted. Nothing is reported in dry-run mode, or for a create whose response carried no record.
+// Record is called synchronously and may be called from several goroutines at once.
+type AuditSink interface {
+	Record(event AuditEvent)
+}
+
+// WithAuditSink sets the sink that receives audit events
+func WithAuditSink(sink AuditSink) ClientOption {
+	return func(c *Client) {
+		c.auditSink = sink
+	}
+}
+
+// audit reports a successful mutating call to the audit sink, if one is set. Dry runs change
+// nothing, and a call without a range key did not identify a record the service wrote, e.g. a
+// create whose response had no data, so neither is reported.
+func (c *Client) audit(controller, action, partitionKey, rangeKey string) {
+	if c.auditSink == nil || c.dryRun != nil || rangeKey == "" {
+		return
+	}
+
+	c.auditSink.Record(AuditEvent{
+		Operation:    operationName(controller, action),
+		PartitionKey: partitionKey,
+		RangeKey:     rangeKey,
+		Time:         c.clock.Now(),
+	})
+}
@@ -0,0 +1,64 @@
+package gdprclient
+
+import (
+	"context"
+	"net/http"
+	"sync"
+	"testing"
+)
+
+// recordingSink is an AuditSink that keeps every event
+type recordingSink struct {
+	mu     sync.Mutex
+	events []AuditEvent
+}
+
+func (s *recordingSink) Record(event AuditEvent) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	s.events = append(s.events, event)
+}
+
+func TestAuditOnlyRecordsWrites(t *testing.T) {
+	tests := []struct {
+		name       string
+		body       string
+		dryRun     bool
+		wantEvents int
+	}{
+		{"created", `{"statusCode":200,"data":{"partition_key":"p","range_key":"r","type":"INFO_REQUEST"}}`, false, 1},
+		{"no record in response", `{"statusCode":200}`, false, 0},
+		{"dry run", "", true, 0},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			transport := roundTripFunc(func(req *http.Request) (*http.Response, error) {
+				return stubResponse(req, http.StatusOK, tt.body), nil
+			})
+			sink := &recordingSink{}
+			options := []ClientOption{WithAuditSink(sink)}
+			if tt.dryRun {
+				options = append(options, WithDryRun(func(*http.Request) {}))
+			}
+			client := newStubClient(t, transport, options...)
+
+			ctx := context.Background()
+			if _, err := client.CreateInfoRequest(ctx, CreateInfoRequestInput{PartitionKey: "p", Type: TypeInfoRequest, CreatedBy: "test"}); err != nil {
+				t.Fatalf("CreateInfoRequest: %v", err)
+			}
+			if tt.dryRun {
+				if _, err := client.DeleteInfoRequest(ctx, DeleteRequestInput{PartitionKey: "p", RangeKey: "r", IsHardDelete: true}); err != nil {
+					t.Fatalf("DeleteInfoRequest: %v", err)
+				}
+			}
+
+			if len(sink.events) != tt.wantEvents {
+				t.Fatalf("audit events = %+v, want %d", sink.events, tt.wantEvents)
+			}
+			if tt.wantEvents > 0 && (sink.events[0].Operation != "create" || sink.events[0].RangeKey != "r") {
+				t.Errorf("audit event = %+v, want create of range key r", sink.events[0])
+			}
+		})
+	}
+}
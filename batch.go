@@ -0,0 +1,222 @@
+package gdprclient
+
+import (
+	"context"
+	"sync"
+)
+
+// DefaultBatchConcurrency is the number of requests a batch call runs in parallel by default
+const DefaultBatchConcurrency = 4
+
+// WithBatchConcurrency sets how many requests batch methods run in parallel
+func WithBatchConcurrency(concurrency int) ClientOption {
+	return func(c *Client) {
+		if concurrency < 1 {
+			concurrency = 1
+		}
+		c.batchConcurrency = concurrency
+	}
+}
+
+// BatchCreateInfoRequests creates an info request for each input, running up to the configured
+// batch concurrency at once. Results and errors are returned in the same order as inputs; a failed
+// item has a zero InfoRequest and a non-nil error, and does not stop the rest of the batch.
+func (c *Client) BatchCreateInfoRequests(ctx context.Context, inputs []CreateInfoRequestInput) ([]InfoRequest, []error) {
+	results := make([]InfoRequest, len(inputs))
+	errs := make([]error, len(inputs))
+
+	c.forEachConcurrently(len(inputs), func(i int) {
+		infoRequest, err := c.CreateInfoRequest(ctx, inputs[i])
+		if err != nil {
+			errs[i] = err
+			return
+		}
+		results[i] = *infoRequest
+	})
+
+	return results, errs
+}
+
+// FetchInfoRequests fetches the info request for each input, running up to the configured batch
+// concurrency at once. Results and errors are returned in the same order as inputs; a failed item
+// has a nil InfoRequest and a non-nil error, and does not stop the rest of the batch.
+func (c *Client) FetchInfoRequests(ctx context.Context, inputs []FetchRequestInput) ([]*InfoRequest, []error) {
+	results := make([]*InfoRequest, len(inputs))
+	errs := make([]error, len(inputs))
+
+	c.forEachConcurrently(len(inputs), func(i int) {
+		results[i], errs[i] = c.FetchInfoRequest(ctx, inputs[i])
+	})
+
+	return results, errs
+}
+
+// DeleteAllResult reports the outcome of DeleteAllByPartitionKey. Deleted, Failed and Errors
+// cover both controllers; InfoRequests and DeleteRequests split the counts by controller.
+type DeleteAllResult struct {
+	Deleted int     // Requests deleted successfully
+	Failed  int     // Requests whose delete call returned an error
+	Errors  []error // The delete errors, in no particular order
+
+	InfoRequests   DeleteCount // Info requests deleted and failed
+	DeleteRequests DeleteCount // Delete requests deleted and failed
+}
+
+// DeleteCount is the number of requests of one controller that DeleteAllByPartitionKey deleted
+// and failed to delete
+type DeleteCount struct {
+	Deleted int
+	Failed  int
+}
+
+// DeleteAllByPartitionKey deletes every info request and delete request stored under a partition
+// key, for full erasure of an account. Both listings are read to the end before anything is deleted,
+// so deletes cannot shift the pages being read; the requests are then deleted with up to the
+// configured batch concurrency. Failed deletes are counted and do not stop the run. A listing error
+// is returned before anything is deleted; context cancellation stops the deletes and is returned
+// along with the counts so far.
+func (c *Client) DeleteAllByPartitionKey(ctx context.Context, partitionKey string, isHardDelete bool) (DeleteAllResult, error) {
+	var result DeleteAllResult
+
+	infoKeys, err := c.infoRangeKeys(ctx, partitionKey)
+	if err != nil {
+		return result, err
+	}
+	deleteKeys, err := c.deleteRangeKeys(ctx, partitionKey)
+	if err != nil {
+		return result, err
+	}
+
+	// Each index below len(infoKeys) is an info request, the rest are delete requests
+	var mu sync.Mutex
+	c.forEachConcurrently(len(infoKeys)+len(deleteKeys), func(i int) {
+		if ctx.Err() != nil {
+			return
+		}
+
+		var err error
+		count := &result.InfoRequests
+		if i < len(infoKeys) {
+			_, err = c.DeleteInfoRequest(ctx, DeleteRequestInput{PartitionKey: partitionKey, RangeKey: infoKeys[i], IsHardDelete: isHardDelete})
+		} else {
+			count = &result.DeleteRequests
+			_, err = c.DeleteRequest(ctx, DeleteRequestInput{PartitionKey: partitionKey, RangeKey: deleteKeys[i-len(infoKeys)], IsHardDelete: isHardDelete})
+		}
+
+		mu.Lock()
+		defer mu.Unlock()
+		if err != nil {
+			count.Failed++
+			result.Failed++
+			result.Errors = append(result.Errors, err)
+			return
+		}
+		count.Deleted++
+		result.Deleted++
+	})
+
+	return result, ctx.Err()
+}
+
+// infoRangeKeys lists the range keys of every info request under a partition key
+func (c *Client) infoRangeKeys(ctx context.Context, partitionKey string) ([]string, error) {
+	var keys []string
+	pager := c.FetchAllInfoRequestsPager(FetchAllRequestInput{PartitionKey: partitionKey})
+	for pager.HasMore() {
+		page, err := pager.Next(ctx)
+		if err != nil {
+			return nil, err
+		}
+		for _, request := range page {
+			keys = append(keys, request.RangeKey)
+		}
+	}
+	return keys, nil
+}
+
+// deleteRangeKeys lists the range keys of every delete request under a partition key
+func (c *Client) deleteRangeKeys(ctx context.Context, partitionKey string) ([]string, error) {
+	var keys []string
+	pager := c.FetchAllDeleteRequestsPager(FetchAllRequestInput{PartitionKey: partitionKey})
+	for pager.HasMore() {
+		page, err := pager.Next(ctx)
+		if err != nil {
+			return nil, err
+		}
+		for _, request := range page {
+			keys = append(keys, request.RangeKey)
+		}
+	}
+	return keys, nil
+}
+
+// SweepResult is the outcome of deleting one request in SweepByStatus
+type SweepResult struct {
+	Request DeleteRequest // The request as listed before the delete
+	Err     error         // The delete error, nil if the request was deleted
+}
+
+// SweepByStatus lists the delete requests with the given status and soft deletes each one for which
+// fn returns true, running up to the configured batch concurrency at once. As in
+// DeleteAllByPartitionKey, the listing is read to the end before anything is deleted: a soft delete
+// moves a request out of the status being listed, so deleting while paging could shift the pages
+// still to be read. Every selected request is reported in the results, in listing order, and
+// failed deletes do not stop the sweep. A listing error is returned before anything is deleted;
+// context cancellation stops the deletes, and requests not yet deleted report the context error,
+// which is also returned. fn is called from the calling goroutine only, one request at a time.
+func (c *Client) SweepByStatus(ctx context.Context, status string, fn func(DeleteRequest) bool) ([]SweepResult, error) {
+	var results []SweepResult
+
+	pager := c.FetchDeleteRequestsByStatusPager(FetchByStatusInput{Status: status})
+	for pager.HasMore() {
+		page, err := pager.Next(ctx)
+		if err != nil {
+			return nil, err
+		}
+		for _, request := range page {
+			if fn(request) {
+				results = append(results, SweepResult{Request: request})
+			}
+		}
+	}
+
+	c.forEachConcurrently(len(results), func(i int) {
+		if err := ctx.Err(); err != nil {
+			results[i].Err = err
+			return
+		}
+
+		_, results[i].Err = c.DeleteRequest(ctx, DeleteRequestInput{
+			PartitionKey: results[i].Request.PartitionKey,
+			RangeKey:     results[i].Request.RangeKey,
+		})
+	})
+
+	return results, ctx.Err()
+}
+
+// forEachConcurrently calls fn for every index in [0, n) using at most batchConcurrency goroutines
+func (c *Client) forEachConcurrently(n int, fn func(i int)) {
+	workers := c.batchConcurrency
+	if workers > n {
+		workers = n
+	}
+
+	indexes := make(chan int)
+	var wg sync.WaitGroup
+	for w := 0; w < workers; w++ {
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			for i := range indexes {
+				fn(i)
+			}
+		}()
+	}
+
+	for i := 0; i < n; i++ {
+		indexes <- i
+	}
+	close(indexes)
+	wg.Wait()
+}
@@ -0,0 +1,146 @@
+package gdprclient_test
+
+import (
+	"context"
+	"errors"
+	"testing"
+
+	"github.com/cincinnatiai/gdprclient"
+	"github.com/cincinnatiai/gdprclient/gdprclienttest"
+)
+
+func TestDeleteAllByPartitionKey(t *testing.T) {
+	// A small page size makes both listings span several pages
+	server := gdprclienttest.NewServer(gdprclienttest.WithPageSize(2))
+	defer server.Close()
+
+	ctx := context.Background()
+	client := server.Client(t)
+	for i := 0; i < 5; i++ {
+		if _, err := client.CreateInfoRequest(ctx, gdprclient.CreateInfoRequestInput{PartitionKey: "user-1", Type: gdprclient.TypeInfoRequest, CreatedBy: "test"}); err != nil {
+			t.Fatalf("CreateInfoRequest: %v", err)
+		}
+	}
+	for i := 0; i < 3; i++ {
+		if _, err := client.CreateDeleteRequest(ctx, gdprclient.CreateDeleteRequestInput{PartitionKey: "user-1", Type: gdprclient.TypeDeleteRequest, CreatedBy: "test"}); err != nil {
+			t.Fatalf("CreateDeleteRequest: %v", err)
+		}
+	}
+	if _, err := client.CreateInfoRequest(ctx, gdprclient.CreateInfoRequestInput{PartitionKey: "user-2", Type: gdprclient.TypeInfoRequest, CreatedBy: "test"}); err != nil {
+		t.Fatalf("CreateInfoRequest: %v", err)
+	}
+
+	result, err := client.DeleteAllByPartitionKey(ctx, "user-1", true)
+	if err != nil {
+		t.Fatalf("DeleteAllByPartitionKey: %v", err)
+	}
+
+	want := gdprclient.DeleteAllResult{
+		Deleted:        8,
+		InfoRequests:   gdprclient.DeleteCount{Deleted: 5},
+		DeleteRequests: gdprclient.DeleteCount{Deleted: 3},
+	}
+	if result.Deleted != want.Deleted || result.Failed != 0 || result.InfoRequests != want.InfoRequests || result.DeleteRequests != want.DeleteRequests {
+		t.Errorf("result = %+v, want %+v", result, want)
+	}
+
+	if remaining := server.InfoRequests(); len(remaining) != 1 || remaining[0].PartitionKey != "user-2" {
+		t.Errorf("info requests left = %+v, want only user-2's", remaining)
+	}
+	if remaining := server.DeleteRequests(); len(remaining) != 0 {
+		t.Errorf("delete requests left = %+v, want none", remaining)
+	}
+}
+
+func TestSweepByStatus(t *testing.T) {
+	server := gdprclienttest.NewServer(gdprclienttest.WithPageSize(2))
+	defer server.Close()
+
+	ctx := context.Background()
+	client := server.Client(t)
+	var keys []string
+	for i := 0; i < 5; i++ {
+		created, err := client.CreateDeleteRequest(ctx, gdprclient.CreateDeleteRequestInput{PartitionKey: "user-1", Type: gdprclient.TypeDeleteRequest, CreatedBy: "test"})
+		if err != nil {
+			t.Fatalf("CreateDeleteRequest: %v", err)
+		}
+		keys = append(keys, created.RangeKey)
+	}
+	// The last request stays pending
+	for _, key := range keys[:4] {
+		if _, err := client.MarkFailed(ctx, gdprclient.TypeDeleteRequest, "user-1", key); err != nil {
+			t.Fatalf("MarkFailed: %v", err)
+		}
+	}
+
+	// Skip keys[1], and remove keys[0] behind the sweep's back so its delete fails
+	results, err := client.SweepByStatus(ctx, gdprclient.StatusFailed, func(r gdprclient.DeleteRequest) bool {
+		if r.RangeKey == keys[0] {
+			if _, err := server.Client(t).DeleteRequest(ctx, gdprclient.DeleteRequestInput{PartitionKey: "user-1", RangeKey: r.RangeKey, IsHardDelete: true}); err != nil {
+				t.Fatalf("DeleteRequest: %v", err)
+			}
+		}
+		return r.RangeKey != keys[1]
+	})
+	if err != nil {
+		t.Fatalf("SweepByStatus: %v", err)
+	}
+
+	wantKeys := []string{keys[0], keys[2], keys[3]}
+	if len(results) != len(wantKeys) {
+		t.Fatalf("results = %+v, want %d", results, len(wantKeys))
+	}
+	for i, result := range results {
+		if result.Request.RangeKey != wantKeys[i] {
+			t.Errorf("results[%d] is %s, want %s", i, result.Request.RangeKey, wantKeys[i])
+		}
+		if wantErr := i == 0; (result.Err != nil) != wantErr || (wantErr && !errors.Is(result.Err, gdprclient.ErrRequestNotFound)) {
+			t.Errorf("results[%d].Err = %v, want a not found error: %v", i, result.Err, wantErr)
+		}
+	}
+
+	want := map[string]string{keys[1]: gdprclient.StatusFailed, keys[2]: gdprclient.StatusDeleted, keys[3]: gdprclient.StatusDeleted, keys[4]: gdprclient.StatusPending}
+	for _, r := range server.DeleteRequests() {
+		if r.Status != want[r.RangeKey] {
+			t.Errorf("request %s has status %s, want %s", r.RangeKey, r.Status, want[r.RangeKey])
+		}
+	}
+}
+
+func TestSweepByStatusStopsWhenCancelled(t *testing.T) {
+	server := gdprclienttest.NewServer(gdprclienttest.WithPageSize(2))
+	defer server.Close()
+
+	client := server.Client(t)
+	for i := 0; i < 5; i++ {
+		created, err := client.CreateDeleteRequest(context.Background(), gdprclient.CreateDeleteRequestInput{PartitionKey: "user-1", Type: gdprclient.TypeDeleteRequest, CreatedBy: "test"})
+		if err != nil {
+			t.Fatalf("CreateDeleteRequest: %v", err)
+		}
+		if _, err := client.MarkFailed(context.Background(), gdprclient.TypeDeleteRequest, "user-1", created.RangeKey); err != nil {
+			t.Fatalf("MarkFailed: %v", err)
+		}
+	}
+
+	// Cancel while the first page is being read, so the second is never fetched
+	ctx, cancel := context.WithCancel(context.Background())
+	defer cancel()
+	seen := 0
+	results, err := client.SweepByStatus(ctx, gdprclient.StatusFailed, func(gdprclient.DeleteRequest) bool {
+		seen++
+		cancel()
+		return true
+	})
+
+	if !errors.Is(err, context.Canceled) || results != nil {
+		t.Fatalf("SweepByStatus = %+v, %v; want no results and context.Canceled", results, err)
+	}
+	if seen != 2 {
+		t.Errorf("fn saw %d requests, want the first page of 2", seen)
+	}
+	for _, r := range server.DeleteRequests() {
+		if r.Status != gdprclient.StatusFailed {
+			t.Errorf("request %s has status %s, want it left %s", r.RangeKey, r.Status, gdprclient.StatusFailed)
+		}
+	}
+}
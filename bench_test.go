@@ -0,0 +1,37 @@
+package gdprclient_test
+
+import (
+	"context"
+	"fmt"
+	"sync/atomic"
+	"testing"
+
+	"github.com/cincinnatiai/gdprclient"
+	"github.com/cincinnatiai/gdprclient/gdprclienttest"
+)
+
+// BenchmarkCreateInfoRequestParallel shares one Client between goroutines; run it with -race to
+// check the client's shared state
+func BenchmarkCreateInfoRequestParallel(b *testing.B) {
+	server := gdprclienttest.NewServer()
+	defer server.Close()
+
+	client := server.Client(b)
+	ctx := context.Background()
+
+	var n int64
+	b.ResetTimer()
+	b.RunParallel(func(pb *testing.PB) {
+		for pb.Next() {
+			input := gdprclient.CreateInfoRequestInput{
+				PartitionKey: fmt.Sprintf("user-%d", atomic.AddInt64(&n, 1)),
+				Type:         gdprclient.TypeInfoRequest,
+				CreatedBy:    "bench",
+			}
+			if _, err := client.CreateInfoRequest(ctx, input); err != nil {
+				b.Error(err)
+				return
+			}
+		}
+	})
+}
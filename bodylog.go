@@ -0,0 +1,82 @@
+package gdprclient
+
+import (
+	"encoding/json"
+	"fmt"
+)
+
+// WithBodyLogging logs request and response bodies through the Logger at debug level, truncated to
+// maxBytes. redact, if not nil, rewrites each body before it is logged or truncated and is also
+// applied to the body of curl commands from WithCurlOnError; RedactJSONFields covers the common
+// case. A maxBytes of zero or less turns body logging off.
+func WithBodyLogging(maxBytes int, redact func(body []byte) []byte) ClientOption {
+	return func(c *Client) {
+		c.bodyLogLimit = maxBytes
+		c.bodyRedactor = redact
+	}
+}
+
+// RedactJSONFields returns a redactor for WithBodyLogging that replaces the value of every
+// object field with one of the given names, at any depth, with "REDACTED". Bodies that are not
+// valid JSON are replaced entirely, since they cannot be scrubbed field by field.
+func RedactJSONFields(fields ...string) func(body []byte) []byte {
+	names := make(map[string]bool, len(fields))
+	for _, field := range fields {
+		names[field] = true
+	}
+
+	return func(body []byte) []byte {
+		var value interface{}
+		if err := json.Unmarshal(body, &value); err != nil {
+			return []byte(redacted)
+		}
+
+		scrubbed, err := json.Marshal(redactValue(value, names))
+		if err != nil {
+			return []byte(redacted)
+		}
+		return scrubbed
+	}
+}
+
+// redactValue replaces the named fields of every object within value
+func redactValue(value interface{}, names map[string]bool) interface{} {
+	switch v := value.(type) {
+	case map[string]interface{}:
+		for key, field := range v {
+			if names[key] {
+				v[key] = redacted
+				continue
+			}
+			v[key] = redactValue(field, names)
+		}
+	case []interface{}:
+		for i, element := range v {
+			v[i] = redactValue(element, names)
+		}
+	}
+	return value
+}
+
+// redactBody applies the configured redactor to a body about to be logged
+func (c *Client) redactBody(body []byte) []byte {
+	if c.bodyRedactor == nil || len(body) == 0 {
+		return body
+	}
+	return c.bodyRedactor(body)
+}
+
+// logBody logs a redacted, truncated request or response body when body logging is enabled
+func (c *Client) logBody(operation, direction string, body []byte) {
+	if c.bodyLogLimit <= 0 {
+		return
+	}
+
+	body = c.redactBody(body)
+	suffix := ""
+	if len(body) > c.bodyLogLimit {
+		suffix = fmt.Sprintf("... (%d more bytes)", len(body)-c.bodyLogLimit)
+		body = body[:c.bodyLogLimit]
+	}
+	c.logger.Debugf("gdprclient: %s %s body: %s%s", operation, direction, body, suffix)
+}
@@ -0,0 +1,112 @@
+package gdprclient
+
+import (
+	"context"
+	"errors"
+	"net/url"
+	"sync"
+	"time"
+)
+
+// ErrCircuitOpen is returned without contacting the service while the circuit breaker is open
+var ErrCircuitOpen = errors.New("circuit breaker is open")
+
+// Circuit breaker states
+const (
+	circuitClosed = iota
+	circuitOpen
+	circuitHalfOpen
+)
+
+// circuitBreaker stops calls after consecutive failures and lets a single trial call through after a cooldown
+type circuitBreaker struct {
+	mu        sync.Mutex
+	threshold int
+	cooldown  time.Duration
+	state     int
+	failures  int
+	openedAt  time.Time
+}
+
+// WithCircuitBreaker opens the circuit after threshold consecutive failed calls: transport errors,
+// or statuses the retry policy retries, once retries are used up. Errors raised by the client
+// itself, such as a cancelled context or a failing interceptor, do not count. While open, calls
+// fail fast with ErrCircuitOpen. After cooldown one trial call is allowed through; its success
+// closes the circuit and its failure opens it again.
+func WithCircuitBreaker(threshold int, cooldown time.Duration) ClientOption {
+	return func(c *Client) {
+		if threshold < 1 {
+			threshold = 1
+		}
+		c.breaker = &circuitBreaker{
+			threshold: threshold,
+			cooldown:  cooldown,
+		}
+	}
+}
+
+// allow reports whether a call may proceed
+func (b *circuitBreaker) allow(now time.Time) bool {
+	b.mu.Lock()
+	defer b.mu.Unlock()
+
+	switch b.state {
+	case circuitOpen:
+		if now.Sub(b.openedAt) < b.cooldown {
+			return false
+		}
+		b.state = circuitHalfOpen
+		return true
+	case circuitHalfOpen:
+		// Only the trial call is allowed until it completes
+		return false
+	}
+	return true
+}
+
+// record updates the breaker with the outcome of a call that reached the service
+func (b *circuitBreaker) record(failed bool, now time.Time) {
+	b.mu.Lock()
+	defer b.mu.Unlock()
+
+	if !failed {
+		b.state = circuitClosed
+		b.failures = 0
+		return
+	}
+
+	b.failures++
+	if b.state == circuitHalfOpen || b.failures >= b.threshold {
+		b.state = circuitOpen
+		b.openedAt = now
+	}
+}
+
+// release frees a trial call whose outcome says nothing about the service, so the next call can try again
+func (b *circuitBreaker) release() {
+	b.mu.Lock()
+	defer b.mu.Unlock()
+
+	if b.state == circuitHalfOpen {
+		b.state = circuitOpen
+	}
+}
+
+// recordBreaker tells the circuit breaker, if any, how a call went. Only transport errors and
+// statuses the retry policy retries count as failures; a call the caller gave up on, or that failed
+// in the client before reaching the service, only releases a trial call.
+func (c *Client) recordBreaker(ctx context.Context, statusCode int, err error) {
+	if c.breaker == nil {
+		return
+	}
+
+	var urlErr *url.Error
+	transport := errors.As(err, &urlErr) || errors.Is(err, errBodyRead)
+	if ctx.Err() != nil || (err != nil && !transport) {
+		c.breaker.release()
+		return
+	}
+
+	failed := err != nil || ShouldRetry(statusCode, nil) || c.retryPolicy.isRetryableStatus(statusCode)
+	c.breaker.record(failed, c.clock.Now())
+}
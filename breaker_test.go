@@ -0,0 +1,89 @@
+package gdprclient
+
+import (
+	"context"
+	"errors"
+	"net/http"
+	"testing"
+	"time"
+)
+
+func TestCircuitBreakerTransitions(t *testing.T) {
+	status := http.StatusServiceUnavailable
+	var calls int
+	transport := roundTripFunc(func(req *http.Request) (*http.Response, error) {
+		calls++
+		return stubResponse(req, status, `{"statusCode":200,"data":{"partition_key":"p","range_key":"r"}}`), nil
+	})
+	clock := &fakeClock{now: time.Date(2025, 3, 24, 12, 0, 0, 0, time.UTC)}
+	client := newStubClient(t, transport, WithRetryPolicy(RetryPolicy{}), WithClock(clock), WithCircuitBreaker(2, time.Minute))
+
+	fetch := func() error {
+		_, err := client.FetchInfoRequest(context.Background(), FetchRequestInput{PartitionKey: "p", RangeKey: "r"})
+		return err
+	}
+	expect := func(step string, wantCalls int, wantOpen bool) {
+		t.Helper()
+		err := fetch()
+		if open := errors.Is(err, ErrCircuitOpen); open != wantOpen {
+			t.Fatalf("%s: err = %v, want circuit open: %v", step, err, wantOpen)
+		}
+		if calls != wantCalls {
+			t.Fatalf("%s: transport called %d times, want %d", step, calls, wantCalls)
+		}
+	}
+
+	expect("first failure", 1, false)
+	expect("second failure opens", 2, false)
+	expect("open", 2, true)
+
+	clock.advance(time.Minute)
+	expect("failed trial", 3, false)
+	expect("reopened", 3, true)
+
+	clock.advance(time.Minute)
+	status = http.StatusOK
+	expect("successful trial", 4, false)
+	expect("closed", 5, false)
+}
+
+func TestCircuitBreakerIgnoresLocalErrors(t *testing.T) {
+	status := http.StatusOK
+	var calls int
+	transport := roundTripFunc(func(req *http.Request) (*http.Response, error) {
+		calls++
+		return stubResponse(req, status, `{"statusCode":200,"data":{"partition_key":"p","range_key":"r"}}`), nil
+	})
+	failInterceptor := true
+	client := newStubClient(t, transport, WithRetryPolicy(RetryPolicy{}), WithCircuitBreaker(1, time.Hour), WithRequestInterceptor(func(req *http.Request) error {
+		if failInterceptor {
+			return errors.New("no credentials")
+		}
+		return nil
+	}))
+	input := FetchRequestInput{PartitionKey: "p", RangeKey: "r"}
+
+	cancelled, cancel := context.WithCancel(context.Background())
+	cancel()
+	if _, err := client.FetchInfoRequest(cancelled, input); !errors.Is(err, context.Canceled) {
+		t.Fatalf("cancelled fetch err = %v, want context.Canceled", err)
+	}
+	if _, err := client.FetchInfoRequest(context.Background(), input); err == nil || errors.Is(err, ErrCircuitOpen) {
+		t.Fatalf("fetch with failing interceptor err = %v, want the interceptor error", err)
+	}
+
+	// A status the retry policy does not retry says the service is up
+	failInterceptor = false
+	status = http.StatusNotFound
+	if _, err := client.FetchInfoRequest(context.Background(), input); !errors.Is(err, ErrRequestNotFound) {
+		t.Fatalf("fetch of a missing request err = %v, want ErrRequestNotFound", err)
+	}
+
+	status = http.StatusOK
+	if _, err := client.FetchInfoRequest(context.Background(), input); err != nil {
+		t.Fatalf("FetchInfoRequest after local errors: %v", err)
+	}
+	if calls != 2 {
+		t.Errorf("transport called %d times, want 2", calls)
+	}
+}
@@ -0,0 +1,190 @@
+package gdprclient
+
+import (
+	"context"
+	"encoding/json"
+	"net/http"
+	"sync"
+	"time"
+)
+
+// responseCache holds the data of fetched requests for a fixed TTL. A nil cache stores nothing,
+// so callers need not check whether caching is enabled.
+type responseCache struct {
+	mu        sync.Mutex
+	ttl       time.Duration
+	entries   map[string]cacheEntry
+	lastSweep time.Time
+}
+
+// cacheEntry is the response data of a fetch, the ETag it was sent with, the time it stops being
+// served without revalidation, and a hash of the API key it was fetched with
+type cacheEntry struct {
+	data       json.RawMessage
+	etag       string
+	expiresAt  time.Time
+	credential string
+}
+
+// WithCache caches the results of FetchInfoRequest and FetchDeleteRequest for ttl, keyed on
+// partition and range key. An entry is only served to fetches made with the API key it was
+// fetched with, so a call with a different ApiKey in its input goes to the service. Updating or
+// deleting a request through this client drops its entry. Changes made by other clients are not
+// seen until the entry expires; a fetch whose context comes from ContextWithBypassCache always
+// asks the service.
+//
+// When the service sends an ETag, an expired entry is kept for a further ttl and the next fetch
+// sends it in If-None-Match; a 304 Not Modified reply serves the cached record again without
+// transferring it.
+func WithCache(ttl time.Duration) ClientOption {
+	return func(c *Client) {
+		if ttl <= 0 {
+			c.cache = nil
+			return
+		}
+		c.cache = &responseCache{
+			ttl:     ttl,
+			entries: make(map[string]cacheEntry),
+		}
+	}
+}
+
+// bypassCacheKey is the context key set by ContextWithBypassCache
+type bypassCacheKey struct{}
+
+// ContextWithBypassCache returns a context whose fetches skip the cache set by WithCache: the
+// request is always sent to the service, and its result is neither served from nor stored in the
+// cache. Use it for reads that must be authoritative, e.g. right before a destructive operation.
+//
+// This stands in for a WithBypassCache call option: the client has no per-call options, and
+// // carries per-call settings in the context instead, as ContextWithCorrelationID does. The client
+// has no WithConsistentRead either, so a bypassed fetch reads whatever the service returns.
+func ContextWithBypassCache(ctx context.Context) context.Context {
+	return context.WithValue(ctx, bypassCacheKey{}, true)
+}
+
+// bypassCache reports whether ctx was returned by ContextWithBypassCache
+func bypassCache(ctx context.Context) bool {
+	bypass, _ := ctx.Value(bypassCacheKey{}).(bool)
+	return bypass
+}
+
+// cacheKey identifies a request within a controller
+func cacheKey(controller, partitionKey, rangeKey string) string {
+	return controller + "|" + partitionKey + "|" + rangeKey
+}
+
+// get returns the entry for key fetched with credential and whether it is still fresh. Expired
+// entries are only returned while they can be revalidated with their ETag.
+func (rc *responseCache) get(key, credential string, now time.Time) (cacheEntry, bool, bool) {
+	if rc == nil {
+		return cacheEntry{}, false, false
+	}
+
+	rc.mu.Lock()
+	defer rc.mu.Unlock()
+
+	entry, ok := rc.entries[key]
+	if !ok || entry.credential != credential {
+		return cacheEntry{}, false, false
+	}
+	if rc.expired(entry, now) {
+		delete(rc.entries, key)
+		return cacheEntry{}, false, false
+	}
+	return entry, now.Before(entry.expiresAt), true
+}
+
+// set stores data and its ETag under key for credential, replacing an entry fetched with another
+// credential, and drops dead entries at most once per TTL
+func (rc *responseCache) set(key, credential string, data json.RawMessage, etag string, now time.Time) {
+	if rc == nil {
+		return
+	}
+
+	rc.mu.Lock()
+	defer rc.mu.Unlock()
+
+	if now.Sub(rc.lastSweep) >= rc.ttl {
+		for k, entry := range rc.entries {
+			if rc.expired(entry, now) {
+				delete(rc.entries, k)
+			}
+		}
+		rc.lastSweep = now
+	}
+
+	rc.entries[key] = cacheEntry{data: data, etag: etag, expiresAt: now.Add(rc.ttl), credential: credential}
+}
+
+// expired reports whether an entry can no longer be served, even after revalidation
+func (rc *responseCache) expired(entry cacheEntry, now time.Time) bool {
+	if entry.etag != "" {
+		return !now.Before(entry.expiresAt.Add(rc.ttl))
+	}
+	return !now.Before(entry.expiresAt)
+}
+
+// invalidate drops the entry for key
+func (rc *responseCache) invalidate(key string) {
+	if rc == nil {
+		return
+	}
+
+	rc.mu.Lock()
+	defer rc.mu.Unlock()
+
+	delete(rc.entries, key)
+}
+
+// fetchCached fetches a request into out, serving it from the cache when fresh and revalidating
+// an expired entry with If-None-Match when it has an ETag
+func (c *Client) fetchCached(ctx context.Context, controller string, input FetchRequestInput, out interface{}) error {
+	if c.cache == nil || bypassCache(ctx) {
+		return c.do(ctx, controller, "fetch", input.ApiKey, input, out)
+	}
+
+	// Entries are tied to the key that fetched them so one tenant's records are never served to another
+	apiKey := input.ApiKey
+	if apiKey == "" {
+		apiKey = c.apiKey
+	}
+	credential := hashHex([]byte(apiKey))
+
+	key := cacheKey(controller, input.PartitionKey, input.RangeKey)
+	entry, fresh, ok := c.cache.get(key, credential, c.clock.Now())
+	if ok && fresh {
+		return c.decodeData(entry.data, out)
+	}
+	if ok {
+		input.ifNoneMatch = entry.etag
+	}
+
+	data, etag, err := c.revalidate(ctx, controller, input, entry, ok)
+	c.stats.record(err)
+	if err != nil {
+		return err
+	}
+
+	c.cache.set(key, credential, data, etag, c.clock.Now())
+	return c.decodeData(data, out)
+}
+
+// revalidate fetches a request for the cache and returns its data and ETag. When cached is set,
+// input carries the entry's ETag in If-None-Match, and a 304 reply returns the entry's data.
+func (c *Client) revalidate(ctx context.Context, controller string, input FetchRequestInput, entry cacheEntry, cached bool) (json.RawMessage, string, error) {
+	statusCode, header, responseBody, err := c.postWithHeader(ctx, controller, "fetch", input.ApiKey, input)
+	if err != nil {
+		return nil, "", err
+	}
+
+	if cached && statusCode == http.StatusNotModified {
+		return entry.data, entry.etag, nil
+	}
+
+	var data json.RawMessage
+	if err := c.decodeResponse(controller, "fetch", statusCode, responseBody, &data); err != nil {
+		return nil, "", err
+	}
+	return data, header.Get("ETag"), nil
+}
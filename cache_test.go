@@ -0,0 +1,346 @@
+package gdprclient_test
+
+import (
+	"context"
+	"errors"
+	"net/http"
+	"sync"
+	"testing"
+	"time"
+
+	"github.com/cincinnatiai/gdprclient"
+	"github.com/cincinnatiai/gdprclient/gdprclienttest"
+)
+
+func TestCacheIsPerAPIKey(t *testing.T) {
+	server := gdprclienttest.NewServer()
+	defer server.Close()
+
+	ctx := context.Background()
+	client := server.Client(t, gdprclient.WithCache(time.Minute))
+
+	created, err := client.CreateInfoRequest(ctx, gdprclient.CreateInfoRequestInput{PartitionKey: "user-1", Type: gdprclient.TypeInfoRequest, CreatedBy: "test"})
+	if err != nil {
+		t.Fatalf("CreateInfoRequest: %v", err)
+	}
+	input := gdprclient.FetchRequestInput{PartitionKey: "user-1", RangeKey: created.RangeKey}
+	if _, err := client.FetchInfoRequest(ctx, input); err != nil {
+		t.Fatalf("FetchInfoRequest: %v", err)
+	}
+
+	input.ApiKey = "wrong-tenant-key"
+	got, err := client.FetchInfoRequest(ctx, input)
+	if got != nil || !errors.Is(err, gdprclient.ErrUnauthorized) {
+		t.Fatalf("FetchInfoRequest with another key = %+v, %v; want the service's 401", got, err)
+	}
+
+	// The rejected fetch must not have evicted the entry for the client's own key
+	input.ApiKey = ""
+	if _, err := client.FetchInfoRequest(ctx, input); err != nil {
+		t.Fatalf("FetchInfoRequest: %v", err)
+	}
+}
+
+func TestContextWithBypassCache(t *testing.T) {
+	server := gdprclienttest.NewServer()
+	defer server.Close()
+
+	ctx := context.Background()
+	cached := server.Client(t, gdprclient.WithCache(time.Minute))
+	other := server.Client(t)
+
+	created, err := cached.CreateInfoRequest(ctx, gdprclient.CreateInfoRequestInput{PartitionKey: "user-1", Type: gdprclient.TypeInfoRequest, CreatedBy: "test"})
+	if err != nil {
+		t.Fatalf("CreateInfoRequest: %v", err)
+	}
+	input := gdprclient.FetchRequestInput{PartitionKey: "user-1", RangeKey: created.RangeKey}
+
+	if _, err := cached.FetchInfoRequest(ctx, input); err != nil {
+		t.Fatalf("FetchInfoRequest: %v", err)
+	}
+	if _, err := other.UpdateInfoRequest(ctx, gdprclient.UpdateRequestInput{PartitionKey: "user-1", RangeKey: created.RangeKey, Status: gdprclient.StatusComplete}); err != nil {
+		t.Fatalf("UpdateInfoRequest: %v", err)
+	}
+
+	fresh, err := cached.FetchInfoRequest(gdprclient.ContextWithBypassCache(ctx), input)
+	if err != nil {
+		t.Fatalf("FetchInfoRequest with bypass: %v", err)
+	}
+	if fresh.Status != gdprclient.StatusComplete {
+		t.Errorf("bypassed fetch status = %q, want %q from the server", fresh.Status, gdprclient.StatusComplete)
+	}
+
+	// The bypassed result must not have replaced the cached entry
+	stale, err := cached.FetchInfoRequest(ctx, input)
+	if err != nil {
+		t.Fatalf("FetchInfoRequest: %v", err)
+	}
+	if stale.Status != gdprclient.StatusPending {
+		t.Errorf("cached fetch status = %q, want the cached %q", stale.Status, gdprclient.StatusPending)
+	}
+}
+
+func TestContextWithBypassCacheDoesNotPopulate(t *testing.T) {
+	server := gdprclienttest.NewServer()
+	defer server.Close()
+
+	ctx := context.Background()
+	cached := server.Client(t, gdprclient.WithCache(time.Minute))
+	other := server.Client(t)
+
+	created, err := cached.CreateDeleteRequest(ctx, gdprclient.CreateDeleteRequestInput{PartitionKey: "user-1", Type: gdprclient.TypeDeleteRequest, CreatedBy: "test"})
+	if err != nil {
+		t.Fatalf("CreateDeleteRequest: %v", err)
+	}
+	input := gdprclient.FetchRequestInput{PartitionKey: "user-1", RangeKey: created.RangeKey}
+
+	if _, err := cached.FetchDeleteRequest(gdprclient.ContextWithBypassCache(ctx), input); err != nil {
+		t.Fatalf("FetchDeleteRequest with bypass: %v", err)
+	}
+	if _, err := other.UpdateDeleteRequest(ctx, gdprclient.UpdateRequestInput{PartitionKey: "user-1", RangeKey: created.RangeKey, Status: gdprclient.StatusFailed}); err != nil {
+		t.Fatalf("UpdateDeleteRequest: %v", err)
+	}
+
+	got, err := cached.FetchDeleteRequest(ctx, input)
+	if err != nil {
+		t.Fatalf("FetchDeleteRequest: %v", err)
+	}
+	if got.Status != gdprclient.StatusFailed {
+		t.Errorf("status = %q, want %q; the bypassed fetch was cached", got.Status, gdprclient.StatusFailed)
+	}
+}
+
+func TestReclassifyRequestReadsPastCache(t *testing.T) {
+	server := gdprclienttest.NewServer()
+	defer server.Close()
+
+	ctx := context.Background()
+	cached := server.Client(t, gdprclient.WithCache(time.Minute))
+	other := server.Client(t)
+
+	created, err := cached.CreateInfoRequest(ctx, gdprclient.CreateInfoRequestInput{PartitionKey: "user-1", Type: gdprclient.TypeInfoRequest, CreatedBy: "test"})
+	if err != nil {
+		t.Fatalf("CreateInfoRequest: %v", err)
+	}
+	if _, err := cached.FetchInfoRequest(ctx, gdprclient.FetchRequestInput{PartitionKey: "user-1", RangeKey: created.RangeKey}); err != nil {
+		t.Fatalf("FetchInfoRequest: %v", err)
+	}
+	if _, err := other.UpdateInfoRequest(ctx, gdprclient.UpdateRequestInput{PartitionKey: "user-1", RangeKey: created.RangeKey, Status: gdprclient.StatusComplete}); err != nil {
+		t.Fatalf("UpdateInfoRequest: %v", err)
+	}
+
+	moved, err := cached.ReclassifyRequest(ctx, "user-1", created.RangeKey, gdprclient.TypeDeleteRequest)
+	if err != nil {
+		t.Fatalf("ReclassifyRequest: %v", err)
+	}
+	if moved.Delete == nil || moved.Delete.Status != gdprclient.StatusComplete {
+		t.Errorf("moved = %+v, want a delete request with the current status %q", moved.Delete, gdprclient.StatusComplete)
+	}
+}
+
+// steppedClock is a Clock that only moves when stepped; its timers fire at once
+type steppedClock struct {
+	mu  sync.Mutex
+	now time.Time
+}
+
+func (c *steppedClock) Now() time.Time {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	return c.now
+}
+
+func (c *steppedClock) NewTimer(d time.Duration) gdprclient.Timer {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	c.now = c.now.Add(d)
+	fired := make(chan time.Time, 1)
+	fired <- c.now
+	return firedTimer(fired)
+}
+
+func (c *steppedClock) step(d time.Duration) {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	c.now = c.now.Add(d)
+}
+
+// firedTimer is a Timer that has already fired
+type firedTimer chan time.Time
+
+func (t firedTimer) C() <-chan time.Time {
+	return t
+}
+
+func (t firedTimer) Stop() bool {
+	return false
+}
+
+// fetchLog records the If-None-Match header and response status of each request a client sends
+type fetchLog struct {
+	mu          sync.Mutex
+	ifNoneMatch []string
+	statuses    []int
+}
+
+func (l *fetchLog) options() []gdprclient.ClientOption {
+	return []gdprclient.ClientOption{
+		gdprclient.WithRequestInterceptor(func(req *http.Request) error {
+			l.mu.Lock()
+			defer l.mu.Unlock()
+			l.ifNoneMatch = append(l.ifNoneMatch, req.Header.Get("If-None-Match"))
+			return nil
+		}),
+		gdprclient.WithResponseInterceptor(func(resp *http.Response) error {
+			l.mu.Lock()
+			defer l.mu.Unlock()
+			l.statuses = append(l.statuses, resp.StatusCode)
+			return nil
+		}),
+	}
+}
+
+// reset forgets the calls logged so far
+func (l *fetchLog) reset() {
+	l.mu.Lock()
+	defer l.mu.Unlock()
+	l.ifNoneMatch, l.statuses = nil, nil
+}
+
+func TestCacheRevalidatesWithETag(t *testing.T) {
+	server := gdprclienttest.NewServer()
+	defer server.Close()
+
+	ctx := context.Background()
+	clock := &steppedClock{now: time.Date(2025, 3, 24, 12, 0, 0, 0, time.UTC)}
+	log := &fetchLog{}
+	cached := server.Client(t, append(log.options(), gdprclient.WithCache(time.Minute), gdprclient.WithClock(clock))...)
+	other := server.Client(t)
+
+	created, err := other.CreateInfoRequest(ctx, gdprclient.CreateInfoRequestInput{PartitionKey: "user-1", Type: gdprclient.TypeInfoRequest, CreatedBy: "test"})
+	if err != nil {
+		t.Fatalf("CreateInfoRequest: %v", err)
+	}
+	input := gdprclient.FetchRequestInput{PartitionKey: "user-1", RangeKey: created.RangeKey}
+
+	fetch := func(step string, wantStatus string) {
+		t.Helper()
+		got, err := cached.FetchInfoRequest(ctx, input)
+		if err != nil {
+			t.Fatalf("%s: FetchInfoRequest: %v", step, err)
+		}
+		if got.Status != wantStatus {
+			t.Fatalf("%s: status = %q, want %q", step, got.Status, wantStatus)
+		}
+	}
+
+	fetch("first fetch", gdprclient.StatusPending)
+	fetch("fresh entry", gdprclient.StatusPending)
+	if len(log.statuses) != 1 || log.ifNoneMatch[0] != "" {
+		t.Fatalf("sent If-None-Match %q, got %v; want one unconditional fetch", log.ifNoneMatch, log.statuses)
+	}
+
+	// An expired entry is revalidated, and served again on 304
+	clock.step(time.Minute)
+	log.reset()
+	fetch("not modified", gdprclient.StatusPending)
+	if len(log.statuses) != 1 || log.ifNoneMatch[0] == "" || log.statuses[0] != http.StatusNotModified {
+		t.Fatalf("sent If-None-Match %q, got %v; want one conditional fetch answered with 304", log.ifNoneMatch, log.statuses)
+	}
+
+	// A change made elsewhere is picked up by the next revalidation
+	if _, err := other.UpdateInfoRequest(ctx, gdprclient.UpdateRequestInput{PartitionKey: "user-1", RangeKey: created.RangeKey, Status: gdprclient.StatusComplete}); err != nil {
+		t.Fatalf("UpdateInfoRequest: %v", err)
+	}
+	clock.step(time.Minute)
+	log.reset()
+	fetch("modified", gdprclient.StatusComplete)
+	if len(log.statuses) != 1 || log.ifNoneMatch[0] == "" || log.statuses[0] != http.StatusOK {
+		t.Fatalf("sent If-None-Match %q, got %v; want one conditional fetch answered with 200", log.ifNoneMatch, log.statuses)
+	}
+}
+
+func TestCacheETagIsPerAPIKey(t *testing.T) {
+	server := gdprclienttest.NewServer()
+	defer server.Close()
+
+	ctx := context.Background()
+	clock := &steppedClock{now: time.Date(2025, 3, 24, 12, 0, 0, 0, time.UTC)}
+	log := &fetchLog{}
+	client := server.Client(t, append(log.options(), gdprclient.WithCache(time.Minute), gdprclient.WithClock(clock))...)
+
+	created, err := client.CreateInfoRequest(ctx, gdprclient.CreateInfoRequestInput{PartitionKey: "user-1", Type: gdprclient.TypeInfoRequest, CreatedBy: "test"})
+	if err != nil {
+		t.Fatalf("CreateInfoRequest: %v", err)
+	}
+	input := gdprclient.FetchRequestInput{PartitionKey: "user-1", RangeKey: created.RangeKey}
+	if _, err := client.FetchInfoRequest(ctx, input); err != nil {
+		t.Fatalf("FetchInfoRequest: %v", err)
+	}
+	clock.step(time.Minute)
+
+	// The ETag fetched with the client's key must not be offered for another key
+	log.reset()
+	other := input
+	other.ApiKey = "wrong-tenant-key"
+	if _, err := client.FetchInfoRequest(ctx, other); !errors.Is(err, gdprclient.ErrUnauthorized) {
+		t.Fatalf("FetchInfoRequest with another key err = %v, want the service's 401", err)
+	}
+	if _, err := client.FetchInfoRequest(ctx, input); err != nil {
+		t.Fatalf("FetchInfoRequest: %v", err)
+	}
+	if len(log.ifNoneMatch) != 2 || log.ifNoneMatch[0] != "" || log.ifNoneMatch[1] == "" {
+		t.Errorf("sent If-None-Match %q, want none for the other key and the ETag for the client's", log.ifNoneMatch)
+	}
+}
+
+func TestCacheInvalidatedByWrites(t *testing.T) {
+	server := gdprclienttest.NewServer()
+	defer server.Close()
+
+	ctx := context.Background()
+	log := &fetchLog{}
+	client := server.Client(t, append(log.options(), gdprclient.WithCache(time.Hour))...)
+
+	created, err := client.CreateDeleteRequest(ctx, gdprclient.CreateDeleteRequestInput{PartitionKey: "user-1", Type: gdprclient.TypeDeleteRequest, CreatedBy: "test"})
+	if err != nil {
+		t.Fatalf("CreateDeleteRequest: %v", err)
+	}
+	input := gdprclient.FetchRequestInput{PartitionKey: "user-1", RangeKey: created.RangeKey}
+
+	writes := []struct {
+		name       string
+		write      func() error
+		wantStatus string
+	}{
+		{"update", func() error {
+			_, err := client.UpdateDeleteRequest(ctx, gdprclient.UpdateRequestInput{PartitionKey: "user-1", RangeKey: created.RangeKey, Status: gdprclient.StatusComplete})
+			return err
+		}, gdprclient.StatusComplete},
+		{"delete", func() error {
+			_, err := client.DeleteRequest(ctx, gdprclient.DeleteRequestInput{PartitionKey: "user-1", RangeKey: created.RangeKey})
+			return err
+		}, gdprclient.StatusDeleted},
+	}
+
+	if _, err := client.FetchDeleteRequest(ctx, input); err != nil {
+		t.Fatalf("FetchDeleteRequest: %v", err)
+	}
+	for _, w := range writes {
+		if err := w.write(); err != nil {
+			t.Fatalf("%s: %v", w.name, err)
+		}
+
+		log.reset()
+		got, err := client.FetchDeleteRequest(ctx, input)
+		if err != nil {
+			t.Fatalf("FetchDeleteRequest after %s: %v", w.name, err)
+		}
+		if got.Status != w.wantStatus {
+			t.Errorf("status after %s = %q, want %q", w.name, got.Status, w.wantStatus)
+		}
+		if len(log.ifNoneMatch) != 1 || log.ifNoneMatch[0] != "" {
+			t.Errorf("fetch after %s sent If-None-Match %q, want one unconditional fetch", w.name, log.ifNoneMatch)
+		}
+	}
+}
@@ -0,0 +1,54 @@
+package gdprclient
+
+import "time"
+
+// Clock tells the time and starts timers. The client reads every timestamp and waits for every
+// backoff, poll interval and rate limiter delay through its Clock, so tests can use WithClock with
+// a fake that advances on demand instead of sleeping.
+type Clock interface {
+	Now() time.Time
+	NewTimer(d time.Duration) Timer
+}
+
+// Timer is a timer started by a Clock. C delivers the time once the duration has passed. Stop
+// prevents the timer from firing and releases it; the client stops every timer it stops waiting
+// for, e.g. when a call is cancelled during a backoff.
+type Timer interface {
+	C() <-chan time.Time
+	Stop() bool
+}
+
+// realClock is the Clock used unless WithClock is set
+type realClock struct{}
+
+func (realClock) Now() time.Time {
+	return time.Now()
+}
+
+func (realClock) NewTimer(d time.Duration) Timer {
+	return realTimer{time.NewTimer(d)}
+}
+
+// realTimer is a Timer backed by a time.Timer
+type realTimer struct {
+	timer *time.Timer
+}
+
+func (t realTimer) C() <-chan time.Time {
+	return t.timer.C
+}
+
+func (t realTimer) Stop() bool {
+	return t.timer.Stop()
+}
+
+// WithClock sets the clock used for timestamps and waits; a nil clock restores the real one.
+// The HTTP client's own timeouts and context deadlines still use real time.
+func WithClock(clock Clock) ClientOption {
+	return func(c *Client) {
+		if clock == nil {
+			clock = realClock{}
+		}
+		c.clock = clock
+	}
+}
@@ -0,0 +1,125 @@
+package gdprclient
+
+import (
+	"context"
+	"errors"
+	"net/http"
+	"sync"
+	"testing"
+	"time"
+)
+
+// fakeClock is a Clock whose time only moves when told to. Its timers never fire unless autoFire
+// is set, in which case each fires at once and moves the time forward by its duration.
+type fakeClock struct {
+	mu       sync.Mutex
+	now      time.Time
+	timers   []*fakeTimer
+	waits    []time.Duration // the duration of each timer, in order
+	autoFire bool
+	onTimer  func() // called after each NewTimer, e.g. to cancel the call that is about to wait
+}
+
+func (c *fakeClock) Now() time.Time {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	return c.now
+}
+
+// advance moves the time forward by d
+func (c *fakeClock) advance(d time.Duration) {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	c.now = c.now.Add(d)
+}
+
+func (c *fakeClock) NewTimer(d time.Duration) Timer {
+	c.mu.Lock()
+	timer := &fakeTimer{c: make(chan time.Time, 1)}
+	c.timers = append(c.timers, timer)
+	c.waits = append(c.waits, d)
+	if c.autoFire {
+		c.now = c.now.Add(d)
+		timer.c <- c.now
+	}
+	c.mu.Unlock()
+
+	if c.onTimer != nil {
+		c.onTimer()
+	}
+	return timer
+}
+
+// fakeTimer is a Timer that records whether it was stopped
+type fakeTimer struct {
+	mu      sync.Mutex
+	c       chan time.Time
+	stopped bool
+}
+
+func (t *fakeTimer) C() <-chan time.Time {
+	return t.c
+}
+
+func (t *fakeTimer) Stop() bool {
+	t.mu.Lock()
+	defer t.mu.Unlock()
+	wasActive := !t.stopped
+	t.stopped = true
+	return wasActive
+}
+
+func TestCancelledWaitsStopTheirTimer(t *testing.T) {
+	const pending = `{"statusCode":200,"data":{"partition_key":"p","range_key":"r","status":"PENDING"}}`
+	input := FetchRequestInput{PartitionKey: "p", RangeKey: "r"}
+
+	tests := []struct {
+		name    string
+		status  int
+		options []ClientOption
+		call    func(ctx context.Context, client *Client) error
+	}{
+		{"retry backoff", http.StatusServiceUnavailable, []ClientOption{WithRetryPolicy(RetryPolicy{MaxRetries: 3, InitialBackoff: time.Hour, MaxBackoff: time.Hour, BackoffFactor: 1})},
+			func(ctx context.Context, client *Client) error {
+				_, err := client.FetchDeleteRequest(ctx, input)
+				return err
+			}},
+		{"rate limiter", http.StatusOK, []ClientOption{WithRateLimiter(1, 1)},
+			func(ctx context.Context, client *Client) error {
+				// The first call takes the only token, so the second waits for the next one
+				if _, err := client.FetchDeleteRequest(context.Background(), input); err != nil {
+					return err
+				}
+				_, err := client.FetchDeleteRequest(ctx, input)
+				return err
+			}},
+		{"status polling", http.StatusOK, nil,
+			func(ctx context.Context, client *Client) error {
+				_, err := client.WaitForStatus(ctx, input, StatusComplete)
+				return err
+			}},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			ctx, cancel := context.WithCancel(context.Background())
+			defer cancel()
+
+			transport := roundTripFunc(func(req *http.Request) (*http.Response, error) {
+				return stubResponse(req, tt.status, pending), nil
+			})
+			clock := &fakeClock{now: time.Date(2025, 3, 24, 0, 0, 0, 0, time.UTC), onTimer: cancel}
+			client := newStubClient(t, transport, append(tt.options, WithClock(clock))...)
+
+			if err := tt.call(ctx, client); !errors.Is(err, context.Canceled) {
+				t.Fatalf("err = %v, want context.Canceled", err)
+			}
+			if len(clock.timers) != 1 {
+				t.Fatalf("started %d timers, want 1", len(clock.timers))
+			}
+			if !clock.timers[0].stopped {
+				t.Error("timer was not stopped after the wait was cancelled")
+			}
+		})
+	}
+}
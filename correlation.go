@@ -0,0 +1,32 @@
+package gdprclient
+
+import "context"
+
+// CorrelationIDHeader carries the caller's correlation ID on every attempt of a call
+const CorrelationIDHeader = "X-Correlation-Id"
+
+// correlationIDKey is the context key for the correlation ID
+type correlationIDKey struct{}
+
+// ContextWithCorrelationID returns a context whose calls send id in CorrelationIDHeader
+func ContextWithCorrelationID(ctx context.Context, id string) context.Context {
+	return context.WithValue(ctx, correlationIDKey{}, id)
+}
+
+// CorrelationIDFromContext returns the ID set by ContextWithCorrelationID, or "" if there is none
+func CorrelationIDFromContext(ctx context.Context) string {
+	id, _ := ctx.Value(correlationIDKey{}).(string)
+	return id
+}
+
+// WithCorrelationIDFunc sets how the correlation ID is read from a call's context, for IDs that an
+// existing tracing or logging package already stores there. The default is CorrelationIDFromContext.
+// No header is sent when the function returns "".
+func WithCorrelationIDFunc(fn func(ctx context.Context) string) ClientOption {
+	return func(c *Client) {
+		if fn == nil {
+			fn = CorrelationIDFromContext
+		}
+		c.correlationID = fn
+	}
+}
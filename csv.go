@@ -0,0 +1,136 @@
+package gdprclient
+
+import (
+	"bytes"
+	"encoding/csv"
+	"fmt"
+	"io"
+	"mime"
+	"net/http"
+	"strings"
+)
+
+// Media types for WithAccept
+const (
+	MediaTypeJSON = "application/json"
+	MediaTypeCSV  = "text/csv"
+)
+
+// LastRangeKeyHeader carries the LastRangeKey of a CSV list response, which has no envelope
+const LastRangeKeyHeader = "X-Last-Range-Key"
+
+// listActions are the actions whose Accept header WithAccept sets
+var listActions = map[string]bool{
+	"fetchAll":         true,
+	"fetchByType":      true,
+	"fetchByStatus":    true,
+	"fetchByCreator":   true,
+	"fetchByDateRange": true,
+}
+
+// WithAccept sets the Accept header of list calls such as FetchAllInfoRequests, e.g. MediaTypeCSV
+// for a backend that can send large listings as CSV. Every other call, and the Stream methods,
+// always accept MediaTypeJSON. A CSV response must start with a header row of Field names, in any
+// order, and carry its cursor in LastRangeKeyHeader.
+func WithAccept(mediaType string) ClientOption {
+	return func(c *Client) {
+		c.accept = mediaType
+	}
+}
+
+// acceptFor returns the Accept header for an action
+func (c *Client) acceptFor(action string) string {
+	if listActions[action] && c.accept != "" {
+		return c.accept
+	}
+	return MediaTypeJSON
+}
+
+// isCSV reports whether a response is CSV by its Content-Type
+func isCSV(header http.Header) bool {
+	mediaType, _, err := mime.ParseMediaType(header.Get("Content-Type"))
+	return err == nil && mediaType == MediaTypeCSV
+}
+
+// decodeCSV decodes a CSV list response into a *PaginatedInfoResponse or *PaginatedDeleteResponse
+func (c *Client) decodeCSV(controller, action string, header http.Header, responseBody []byte, out interface{}) error {
+	results, err := c.parseCSV(responseBody)
+	if err != nil {
+		return fmt.Errorf("failed to decode CSV response for %s: %v", operationName(controller, action), err)
+	}
+	lastRangeKey := header.Get(LastRangeKeyHeader)
+
+	switch page := out.(type) {
+	case *PaginatedInfoResponse:
+		page.Results = results
+		page.LastRangeKey = lastRangeKey
+	case *PaginatedDeleteResponse:
+		page.Results = make([]DeleteRequest, len(results))
+		for i, result := range results {
+			page.Results[i] = DeleteRequest(result)
+		}
+		page.LastRangeKey = lastRangeKey
+	default:
+		return fmt.Errorf("CSV response for %s cannot be decoded into %T", operationName(controller, action), out)
+	}
+	return nil
+}
+
+// parseCSV reads records whose columns are named by the header row. Unknown columns are ignored,
+// or rejected with WithStrictDecoding.
+func (c *Client) parseCSV(data []byte) ([]InfoRequest, error) {
+	reader := csv.NewReader(bytes.NewReader(data))
+	columns, err := reader.Read()
+	if err == io.EOF {
+		return []InfoRequest{}, nil
+	}
+	if err != nil {
+		return nil, err
+	}
+
+	for i, column := range columns {
+		columns[i] = strings.TrimSpace(column)
+		if c.strictDecoding {
+			if err := oneOf("column", columns[i], validFields, true); err != nil {
+				return nil, err
+			}
+		}
+	}
+
+	results := []InfoRequest{}
+	for {
+		row, err := reader.Read()
+		if err == io.EOF {
+			return results, nil
+		}
+		if err != nil {
+			return nil, err
+		}
+
+		var result InfoRequest
+		for i, value := range row {
+			setField(&result, columns[i], value)
+		}
+		results = append(results, result)
+	}
+}
+
+// setField sets the field with the given JSON name, ignoring unknown names
+func setField(r *InfoRequest, name, value string) {
+	switch name {
+	case FieldPartitionKey:
+		r.PartitionKey = value
+	case FieldRangeKey:
+		r.RangeKey = value
+	case FieldType:
+		r.Type = value
+	case FieldStatus:
+		r.Status = value
+	case FieldCreated:
+		r.Created = value
+	case FieldModified:
+		r.Modified = value
+	case FieldCreatedBy:
+		r.CreatedBy = value
+	}
+}
@@ -0,0 +1,97 @@
+package gdprclient
+
+import (
+	"encoding/json"
+	"io"
+	"net/http"
+	"sort"
+	"strings"
+)
+
+// redacted replaces credentials in logged curl commands
+const redacted = "REDACTED"
+
+// sensitiveHeaders are never logged in full
+var sensitiveHeaders = map[string]bool{
+	"Authorization": true,
+	"X-Api-Key":     true,
+}
+
+// logCurl logs req as a curl command if WithCurlOnError is configured
+func (c *Client) logCurl(req *http.Request) {
+	if !c.curlOnError || req == nil {
+		return
+	}
+	c.logger.Infof("gdprclient: request failed, reproduce with: %s", curlCommand(req, c.authHeader, c.redactBody))
+}
+
+// logCurlOnEnvelopeError logs the request behind a 200 response as a curl command if the response
+// envelope carries an error, which is how the service reports most failures
+func (c *Client) logCurlOnEnvelopeError(resp *http.Response, responseBody []byte) {
+	if !c.curlOnError || resp.StatusCode != http.StatusOK || isCSV(resp.Header) {
+		return
+	}
+
+	var envelope struct {
+		StatusCode int `json:"statusCode"`
+	}
+	if err := json.Unmarshal(responseBody, &envelope); err != nil || envelope.StatusCode != http.StatusOK {
+		c.logCurl(resp.Request)
+	}
+}
+
+// curlCommand renders req as an equivalent curl command with credentials, including authHeader,
+// redacted and the body passed through redactBody
+func curlCommand(req *http.Request, authHeader string, redactBody func([]byte) []byte) string {
+	var b strings.Builder
+	b.WriteString("curl -X ")
+	b.WriteString(req.Method)
+	b.WriteString(" ")
+	b.WriteString(shellQuote(req.URL.String()))
+
+	names := make([]string, 0, len(req.Header))
+	for name := range req.Header {
+		names = append(names, name)
+	}
+	sort.Strings(names)
+
+	for _, name := range names {
+		for _, value := range req.Header[name] {
+			if sensitiveHeaders[http.CanonicalHeaderKey(name)] || http.CanonicalHeaderKey(name) == http.CanonicalHeaderKey(authHeader) {
+				value = redacted
+			}
+			b.WriteString(" -H ")
+			b.WriteString(shellQuote(name + ": " + value))
+		}
+	}
+
+	if body := redactBody(requestBody(req)); len(body) > 0 {
+		b.WriteString(" --data-raw ")
+		b.WriteString(shellQuote(string(body)))
+	}
+
+	return b.String()
+}
+
+// requestBody returns a fresh copy of the request body without consuming the original
+func requestBody(req *http.Request) []byte {
+	if req.GetBody == nil {
+		return nil
+	}
+	body, err := req.GetBody()
+	if err != nil {
+		return nil
+	}
+	defer body.Close()
+
+	data, err := io.ReadAll(body)
+	if err != nil {
+		return nil
+	}
+	return data
+}
+
+// shellQuote wraps s in single quotes so it is passed to the shell verbatim
+func shellQuote(s string) string {
+	return "'" + strings.ReplaceAll(s, "'", `'\''`) + "'"
+}
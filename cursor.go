@@ -0,0 +1,41 @@
+package gdprclient
+
+import (
+	"encoding/base64"
+	"encoding/json"
+	"fmt"
+)
+
+// cursor is the content of a token from InfoRequestPager.Cursor or DeleteRequestPager.Cursor.
+// The controller and action tie the LastRangeKey to the listing it came from, since a key is
+// only meaningful to the action that returned it.
+type cursor struct {
+	Controller   string `json:"c"`
+	Action       string `json:"a"`
+	LastRangeKey string `json:"k"`
+}
+
+// encodeCursor returns an opaque, URL-safe token for resuming a listing
+func encodeCursor(controller, action, lastRangeKey string) string {
+	data, _ := json.Marshal(cursor{Controller: controller, Action: action, LastRangeKey: lastRangeKey})
+	return base64.RawURLEncoding.EncodeToString(data)
+}
+
+// decodeCursor returns the LastRangeKey of a token, or an error wrapping ErrInvalidInput if the
+// token is malformed or belongs to a different controller or action
+func decodeCursor(token, controller, action string) (string, error) {
+	data, err := base64.RawURLEncoding.DecodeString(token)
+	if err != nil {
+		return "", fmt.Errorf("%w: malformed cursor: %v", ErrInvalidInput, err)
+	}
+
+	var c cursor
+	if err := json.Unmarshal(data, &c); err != nil {
+		return "", fmt.Errorf("%w: malformed cursor: %v", ErrInvalidInput, err)
+	}
+	if c.Controller != controller || c.Action != action {
+		return "", fmt.Errorf("%w: cursor is for %s, not %s", ErrInvalidInput, operationName(c.Controller, c.Action), operationName(controller, action))
+	}
+
+	return c.LastRangeKey, nil
+}
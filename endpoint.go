@@ -0,0 +1,74 @@
+package gdprclient
+
+import (
+	"net/http"
+	"net/url"
+)
+
+// Endpoint is where and how a controller action is sent
+type Endpoint struct {
+	Method string     // HTTP method; empty means POST. The JSON input is sent as the body for every method.
+	Path   string     // Path appended to the base URL and base path, e.g. "/delete/create"; may be empty
+	Query  url.Values // Query parameters, e.g. controller and action for the default scheme
+}
+
+// EndpointResolver maps a controller action to an Endpoint. An empty controller means info requests.
+type EndpointResolver interface {
+	Resolve(controller, action string) Endpoint
+}
+
+// EndpointResolverFunc adapts a function to an EndpointResolver
+type EndpointResolverFunc func(controller, action string) Endpoint
+
+// Resolve calls f(controller, action)
+func (f EndpointResolverFunc) Resolve(controller, action string) Endpoint {
+	return f(controller, action)
+}
+
+// QueryEndpoints is the default resolver. It sends every action to the base path with the action,
+// and the controller if any, in the query: "/gdpr?action=create&controller=delete".
+var QueryEndpoints EndpointResolver = EndpointResolverFunc(func(controller, action string) Endpoint {
+	query := url.Values{}
+	if controller != "" {
+		query.Set("controller", controller)
+	}
+	query.Set("action", action)
+	return Endpoint{Method: http.MethodPost, Query: query}
+})
+
+// PathEndpoints sends actions as path segments under the base path: "/gdpr/delete/create",
+// and "/gdpr/create" for info requests
+var PathEndpoints EndpointResolver = EndpointResolverFunc(func(controller, action string) Endpoint {
+	path := "/" + url.PathEscape(action)
+	if controller != "" {
+		path = "/" + url.PathEscape(controller) + path
+	}
+	return Endpoint{Method: http.MethodPost, Path: path}
+})
+
+// WithEndpointResolver sets how controller actions map to HTTP method, path and query, e.g.
+// PathEndpoints for a REST-style gateway. The default is QueryEndpoints.
+func WithEndpointResolver(resolver EndpointResolver) ClientOption {
+	return func(c *Client) {
+		if resolver == nil {
+			resolver = QueryEndpoints
+		}
+		c.endpoints = resolver
+	}
+}
+
+// endpoint resolves a controller action to its HTTP method and full URL
+func (c *Client) endpoint(controller, action string) (string, string) {
+	endpoint := c.endpoints.Resolve(controller, action)
+
+	method := endpoint.Method
+	if method == "" {
+		method = http.MethodPost
+	}
+
+	target := c.baseURL + c.basePath + endpoint.Path
+	if len(endpoint.Query) > 0 {
+		target += "?" + endpoint.Query.Encode()
+	}
+	return method, target
+}
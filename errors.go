@@ -0,0 +1,175 @@
+package gdprclient
+
+import (
+	"encoding/json"
+	"errors"
+	"fmt"
+	"net/http"
+	"strings"
+)
+
+// ErrAlreadyExists is returned by create methods when the record already exists
+// and WithExistingOnConflict is enabled
+var ErrAlreadyExists = errors.New("request already exists")
+
+// ErrRequestNotFound matches, via errors.Is, any error for a request the service could not find
+var ErrRequestNotFound = errors.New("request not found")
+
+// ErrConflict matches, via errors.Is, any error for a write the service rejected because the
+// request changed since it was read, e.g. an update whose IfMatch no longer matches Modified
+var ErrConflict = errors.New("request was modified concurrently")
+
+// ErrUnauthorized matches, via errors.Is, any error for a call the service rejected with 401 or 403
+var ErrUnauthorized = errors.New("unauthorized")
+
+// ErrInvalidTransition is returned by update methods when the requested status cannot follow the
+// request's current status; see WithStatusTransitionCheck
+var ErrInvalidTransition = errors.New("invalid status transition")
+
+// ErrUnexpectedResponse is returned with WithStrictDecoding when response data does not have the
+// expected shape
+var ErrUnexpectedResponse = errors.New("unexpected response data")
+
+// ErrNoRecord is returned by update methods when the service reported success but sent no record
+var ErrNoRecord = errors.New("response carried no record")
+
+// ErrNotSupported is returned by methods that need an action the backend does not implement
+var ErrNotSupported = errors.New("not supported by the service")
+
+// ErrResponseTooLarge is returned when a response body exceeds the configured maximum size
+var ErrResponseTooLarge = errors.New("response body too large")
+
+// ErrClientClosed is returned by calls made after Close
+var ErrClientClosed = errors.New("client is closed")
+
+// ErrRequestFailed is returned by WaitForStatus when the request ends in StatusFailed
+var ErrRequestFailed = errors.New("request ended in status " + StatusFailed)
+
+// AlreadyExistsError carries the existing record returned by the service on a 409 from a create.
+// Record is an *InfoRequest or *DeleteRequest depending on the method called.
+type AlreadyExistsError struct {
+	Record interface{}
+}
+
+func (e *AlreadyExistsError) Error() string {
+	return ErrAlreadyExists.Error()
+}
+
+func (e *AlreadyExistsError) Unwrap() error {
+	return ErrAlreadyExists
+}
+
+// decodeConflict reports whether a create response is a 409 conflict carrying the existing record
+// and, if so, decodes that record from the response data into out. A conflict without a record is
+// left to decodeResponse, which reports it as an APIError.
+func decodeConflict(statusCode int, responseBody []byte, out interface{}) (bool, error) {
+	// A non-JSON body is not the service's conflict response; decodeResponse reports it
+	if !json.Valid(responseBody) {
+		return false, nil
+	}
+
+	var response Response
+	if err := json.Unmarshal(responseBody, &response); err != nil {
+		if statusCode == http.StatusConflict {
+			return true, fmt.Errorf("failed to unmarshal response: %v", err)
+		}
+		return false, nil
+	}
+
+	if statusCode != http.StatusConflict && response.StatusCode != http.StatusConflict {
+		return false, nil
+	}
+	if response.Data == nil {
+		return false, nil
+	}
+
+	dataJSON, err := json.Marshal(response.Data)
+	if err != nil {
+		return true, fmt.Errorf("failed to marshal data: %v", err)
+	}
+
+	if err := json.Unmarshal(dataJSON, out); err != nil {
+		return true, fmt.Errorf("failed to unmarshal data: %v", err)
+	}
+
+	return true, nil
+}
+
+// APIError is returned when the GDPR service responds with a non-200 status, either at the HTTP
+// level or in the response envelope, and when a response body is not JSON at all. A non-JSON body
+// usually comes from a gateway or proxy in front of the service, e.g. an HTML page on a 502, and
+// is kept in RawBody; RawBody is nil for errors reported by the service itself.
+type APIError struct {
+	StatusCode     int    // HTTP status code, or the envelope status code when the HTTP status was 200
+	ServiceMessage string // Message returned by the service, if any
+	Controller     string // Controller that was called; empty for info requests
+	Action         string // Action that was called, e.g. "create" or "fetchAll"
+	RawBody        []byte // Response body when it was not JSON
+}
+
+// Error includes the service's message whenever it sent one, for HTTP and envelope errors alike
+func (e *APIError) Error() string {
+	if e.ServiceMessage == "" {
+		return fmt.Sprintf("GDPR service returned error for %s (status %d)", operationName(e.Controller, e.Action), e.StatusCode)
+	}
+	return fmt.Sprintf("GDPR service returned error for %s (status %d): %s", operationName(e.Controller, e.Action), e.StatusCode, e.ServiceMessage)
+}
+
+// Is lets errors.Is match a 404 APIError against ErrRequestNotFound, a 409 or 412 against
+// ErrConflict and a 401 or 403 against ErrUnauthorized
+func (e *APIError) Is(target error) bool {
+	switch target {
+	case ErrRequestNotFound:
+		return e.StatusCode == http.StatusNotFound
+	case ErrUnauthorized:
+		return e.StatusCode == http.StatusUnauthorized || e.StatusCode == http.StatusForbidden
+	case ErrConflict:
+		return e.StatusCode == http.StatusConflict || e.StatusCode == http.StatusPreconditionFailed
+	}
+	return false
+}
+
+// newAPIError builds an APIError for a non-200 HTTP response or a non-JSON body, using the envelope
+// message when present and keeping a non-JSON body in RawBody
+func newAPIError(controller, action string, statusCode int, responseBody []byte) *APIError {
+	apiErr := &APIError{
+		StatusCode:     statusCode,
+		ServiceMessage: strings.TrimSpace(string(responseBody)),
+		Controller:     controller,
+		Action:         action,
+	}
+
+	if !json.Valid(responseBody) {
+		apiErr.RawBody = responseBody
+		return apiErr
+	}
+
+	var response Response
+	if err := json.Unmarshal(responseBody, &response); err == nil && response.Message != "" {
+		apiErr.ServiceMessage = response.Message
+	}
+	return apiErr
+}
+
+// IsGatewayError reports whether err is an APIError for a response whose body was not JSON,
+// which the GDPR service never sends
+func IsGatewayError(err error) bool {
+	var apiErr *APIError
+	return errors.As(err, &apiErr) && apiErr.RawBody != nil
+}
+
+// IsNotFound reports whether err is an APIError with status 404
+func IsNotFound(err error) bool {
+	return hasStatus(err, http.StatusNotFound)
+}
+
+// IsRateLimited reports whether err is an APIError with status 429
+func IsRateLimited(err error) bool {
+	return hasStatus(err, http.StatusTooManyRequests)
+}
+
+// hasStatus reports whether err is an APIError with the given status code
+func hasStatus(err error, statusCode int) bool {
+	var apiErr *APIError
+	return errors.As(err, &apiErr) && apiErr.StatusCode == statusCode
+}
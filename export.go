@@ -0,0 +1,178 @@
+package gdprclient
+
+import (
+	"archive/tar"
+	"compress/gzip"
+	"context"
+	"crypto/sha256"
+	"encoding/hex"
+	"encoding/json"
+	"fmt"
+	"io"
+	"time"
+)
+
+// ArchiveOptions controls the format of an archive written by ExportSubjectArchive
+type ArchiveOptions struct {
+	Compress bool // Wrap the tar stream in gzip
+	Manifest bool // Append a manifest.json listing every record file with its SHA-256
+}
+
+// ArchiveManifest is the content of manifest.json in an exported archive
+type ArchiveManifest struct {
+	PartitionKey string         `json:"partition_key"`
+	ExportedAt   string         `json:"exported_at"`
+	Files        []ArchiveEntry `json:"files"`
+}
+
+// ArchiveEntry describes a single record file in an exported archive
+type ArchiveEntry struct {
+	Name   string `json:"name"`
+	SHA256 string `json:"sha256"`
+	Size   int64  `json:"size"`
+}
+
+// ExportSubjectArchive streams every info request and delete request for a partition key to w as
+// a tar archive with one JSON file per record, under records/info/ and records/delete/ respectively,
+// and returns the number of records written. Pages are written as they are fetched, so memory use
+// is bounded by the page size rather than the subject's history. If an error is returned the
+// archive is incomplete and should be discarded.
+func (c *Client) ExportSubjectArchive(ctx context.Context, partitionKey string, w io.Writer, opts ArchiveOptions) (int, error) {
+	out := w
+	var gz *gzip.Writer
+	if opts.Compress {
+		gz = gzip.NewWriter(w)
+		out = gz
+	}
+	tw := tar.NewWriter(out)
+
+	exportedAt := c.clock.Now().UTC()
+	var manifest *ArchiveManifest
+	if opts.Manifest {
+		manifest = &ArchiveManifest{
+			PartitionKey: partitionKey,
+			ExportedAt:   exportedAt.Format(time.RFC3339),
+			Files:        []ArchiveEntry{},
+		}
+	}
+
+	count := 0
+	writeRecord := func(name string, record interface{}) error {
+		data, err := json.Marshal(record)
+		if err != nil {
+			return fmt.Errorf("failed to marshal record: %v", err)
+		}
+		if err := writeArchiveFile(tw, name, data, exportedAt); err != nil {
+			return err
+		}
+
+		if manifest != nil {
+			sum := sha256.Sum256(data)
+			manifest.Files = append(manifest.Files, ArchiveEntry{
+				Name:   name,
+				SHA256: hex.EncodeToString(sum[:]),
+				Size:   int64(len(data)),
+			})
+		}
+		count++
+		return nil
+	}
+
+	infoPager := c.FetchAllInfoRequestsPager(FetchAllRequestInput{PartitionKey: partitionKey})
+	for infos := 0; infoPager.HasMore(); {
+		page, err := infoPager.Next(ctx)
+		if err != nil {
+			return count, err
+		}
+		for _, record := range page {
+			infos++
+			if err := writeRecord(fmt.Sprintf("records/info/%06d.json", infos), record); err != nil {
+				return count, err
+			}
+		}
+	}
+
+	deletePager := c.FetchAllDeleteRequestsPager(FetchAllRequestInput{PartitionKey: partitionKey})
+	for deletes := 0; deletePager.HasMore(); {
+		page, err := deletePager.Next(ctx)
+		if err != nil {
+			return count, err
+		}
+		for _, record := range page {
+			deletes++
+			if err := writeRecord(fmt.Sprintf("records/delete/%06d.json", deletes), record); err != nil {
+				return count, err
+			}
+		}
+	}
+
+	if manifest != nil {
+		data, err := json.MarshalIndent(manifest, "", "  ")
+		if err != nil {
+			return count, fmt.Errorf("failed to marshal manifest: %v", err)
+		}
+		if err := writeArchiveFile(tw, "manifest.json", data, exportedAt); err != nil {
+			return count, err
+		}
+	}
+
+	if err := tw.Close(); err != nil {
+		return count, fmt.Errorf("failed to finish archive: %v", err)
+	}
+	if gz != nil {
+		if err := gz.Close(); err != nil {
+			return count, fmt.Errorf("failed to finish compression: %v", err)
+		}
+	}
+
+	return count, nil
+}
+
+// ExportInfoRequests writes every info request for a partition key to w as newline-delimited JSON,
+// one record per line, and returns the number written. Pages are written as they are fetched, and
+// if w has a Flush method (e.g. a *bufio.Writer) it is flushed after each page.
+func (c *Client) ExportInfoRequests(ctx context.Context, partitionKey string, w io.Writer) (int, error) {
+	flusher, _ := w.(interface{ Flush() error })
+	encoder := json.NewEncoder(w)
+	pager := c.FetchAllInfoRequestsPager(FetchAllRequestInput{PartitionKey: partitionKey})
+
+	count := 0
+	for pager.HasMore() {
+		results, err := pager.Next(ctx)
+		if err != nil {
+			return count, err
+		}
+
+		for _, result := range results {
+			if err := encoder.Encode(result); err != nil {
+				return count, fmt.Errorf("failed to write record: %v", err)
+			}
+			count++
+		}
+
+		if flusher != nil {
+			if err := flusher.Flush(); err != nil {
+				return count, fmt.Errorf("failed to flush output: %v", err)
+			}
+		}
+	}
+
+	return count, nil
+}
+
+// writeArchiveFile writes a single regular file entry to the tar stream
+func writeArchiveFile(tw *tar.Writer, name string, data []byte, modTime time.Time) error {
+	header := &tar.Header{
+		Name:    name,
+		Mode:    0o644,
+		Size:    int64(len(data)),
+		ModTime: modTime,
+	}
+	if err := tw.WriteHeader(header); err != nil {
+		return fmt.Errorf("failed to write archive header for %s: %v", name, err)
+	}
+	if _, err := tw.Write(data); err != nil {
+		return fmt.Errorf("failed to write archive entry %s: %v", name, err)
+	}
+	return nil
+}
@@ -0,0 +1,139 @@
+package gdprclient_test
+
+import (
+	"archive/tar"
+	"bytes"
+	"compress/gzip"
+	"context"
+	"crypto/sha256"
+	"encoding/hex"
+	"encoding/json"
+	"io"
+	"reflect"
+	"strings"
+	"testing"
+
+	"github.com/cincinnatiai/gdprclient"
+	"github.com/cincinnatiai/gdprclient/gdprclienttest"
+)
+
+func TestExportSubjectArchive(t *testing.T) {
+	// A small page size makes both listings span several pages
+	server := gdprclienttest.NewServer(gdprclienttest.WithPageSize(2))
+	defer server.Close()
+
+	ctx := context.Background()
+	client := server.Client(t)
+	for i := 0; i < 3; i++ {
+		if _, err := client.CreateInfoRequest(ctx, gdprclient.CreateInfoRequestInput{PartitionKey: "user-1", Type: gdprclient.TypeInfoRequest, CreatedBy: "test"}); err != nil {
+			t.Fatalf("CreateInfoRequest: %v", err)
+		}
+	}
+	for i := 0; i < 2; i++ {
+		if _, err := client.CreateDeleteRequest(ctx, gdprclient.CreateDeleteRequestInput{PartitionKey: "user-1", Type: gdprclient.TypeDeleteRequest, CreatedBy: "test"}); err != nil {
+			t.Fatalf("CreateDeleteRequest: %v", err)
+		}
+	}
+	if _, err := client.CreateInfoRequest(ctx, gdprclient.CreateInfoRequestInput{PartitionKey: "user-2", Type: gdprclient.TypeInfoRequest, CreatedBy: "test"}); err != nil {
+		t.Fatalf("CreateInfoRequest: %v", err)
+	}
+
+	records := []string{
+		"records/info/000001.json",
+		"records/info/000002.json",
+		"records/info/000003.json",
+		"records/delete/000001.json",
+		"records/delete/000002.json",
+	}
+
+	tests := []struct {
+		name string
+		opts gdprclient.ArchiveOptions
+	}{
+		{"plain", gdprclient.ArchiveOptions{}},
+		{"compressed with manifest", gdprclient.ArchiveOptions{Compress: true, Manifest: true}},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			var buf bytes.Buffer
+			count, err := client.ExportSubjectArchive(ctx, "user-1", &buf, tt.opts)
+			if err != nil {
+				t.Fatalf("ExportSubjectArchive: %v", err)
+			}
+			if count != len(records) {
+				t.Errorf("count = %d, want %d", count, len(records))
+			}
+
+			var r io.Reader = &buf
+			if tt.opts.Compress {
+				gz, err := gzip.NewReader(r)
+				if err != nil {
+					t.Fatalf("gzip.NewReader: %v", err)
+				}
+				r = gz
+			}
+
+			var names []string
+			files := make(map[string][]byte)
+			tr := tar.NewReader(r)
+			for {
+				header, err := tr.Next()
+				if err == io.EOF {
+					break
+				}
+				if err != nil {
+					t.Fatalf("reading archive: %v", err)
+				}
+				data, err := io.ReadAll(tr)
+				if err != nil {
+					t.Fatalf("reading %s: %v", header.Name, err)
+				}
+				names = append(names, header.Name)
+				files[header.Name] = data
+			}
+
+			want := records
+			if tt.opts.Manifest {
+				want = append(append([]string(nil), records...), "manifest.json")
+			}
+			if !reflect.DeepEqual(names, want) {
+				t.Fatalf("archive holds %q, want %q", names, want)
+			}
+
+			for _, name := range records {
+				var record struct {
+					PartitionKey string `json:"partition_key"`
+					Type         string `json:"type"`
+				}
+				if err := json.Unmarshal(files[name], &record); err != nil {
+					t.Fatalf("%s: %v", name, err)
+				}
+				wantType := gdprclient.TypeInfoRequest
+				if strings.HasPrefix(name, "records/delete/") {
+					wantType = gdprclient.TypeDeleteRequest
+				}
+				if record.PartitionKey != "user-1" || record.Type != wantType {
+					t.Errorf("%s holds %+v, want a %s for user-1", name, record, wantType)
+				}
+			}
+
+			if !tt.opts.Manifest {
+				return
+			}
+			var manifest gdprclient.ArchiveManifest
+			if err := json.Unmarshal(files["manifest.json"], &manifest); err != nil {
+				t.Fatalf("manifest.json: %v", err)
+			}
+			if manifest.PartitionKey != "user-1" || len(manifest.Files) != len(records) {
+				t.Fatalf("manifest = %+v, want %d files for user-1", manifest, len(records))
+			}
+			for i, entry := range manifest.Files {
+				sum := sha256.Sum256(files[entry.Name])
+				if entry.Name != records[i] || entry.SHA256 != hex.EncodeToString(sum[:]) || entry.Size != int64(len(files[entry.Name])) {
+					t.Errorf("manifest entry %+v does not match %s", entry, records[i])
+				}
+			}
+		})
+	}
+}
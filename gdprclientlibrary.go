@@ -9,7 +9,14 @@ import (
 	"io"
 	"math"
 	"math/rand"
+	"net"
 	"net/http"
+	"net/url"
+	"sort"
+	"strconv"
+	"strings"
+	"sync"
+	"syscall"
 	"time"
 )
 
@@ -24,15 +31,107 @@ const (
 	StatusDeleted  = "DELETED"
 )
 
+// terminalStatuses are the statuses in which a request has finished processing
+var terminalStatuses = []string{StatusComplete, StatusFailed, StatusDeleted}
+
+// TerminalStatuses returns the statuses in which a request has finished processing. A finished
+// request is not processed again, but its status can still change: a completed or failed request
+// may be marked StatusDeleted, and a deleted one restored with RestoreInfoRequest or RestoreRequest.
+func TerminalStatuses() []string {
+	statuses := make([]string, len(terminalStatuses))
+	copy(statuses, terminalStatuses)
+	return statuses
+}
+
+// IsTerminal reports whether status is one of the TerminalStatuses
+func IsTerminal(status string) bool {
+	for _, terminal := range terminalStatuses {
+		if status == terminal {
+			return true
+		}
+	}
+	return false
+}
+
+// Environments accepted by WithEnvironment. The environment is sent in EnvironmentHeader
+// so the backend can route the request to the matching stage.
+const (
+	EnvironmentProd    = "Prod"
+	EnvironmentStaging = "Staging"
+	EnvironmentDev     = "Dev"
+)
+
+// EnvironmentHeader is the header that carries the configured environment on every request
+const EnvironmentHeader = "X-Environment"
+
+// validEnvironments are the values accepted by WithEnvironment
+var validEnvironments = []string{EnvironmentProd, EnvironmentStaging, EnvironmentDev}
+
+// Field names accepted in the Fields projection of list inputs. The key fields
+// FieldPartitionKey and FieldRangeKey are always returned, even when not requested.
+const (
+	FieldPartitionKey = "partition_key"
+	FieldRangeKey     = "range_key"
+	FieldType         = "type"
+	FieldStatus       = "status"
+	FieldCreated      = "created"
+	FieldModified     = "modified"
+	FieldCreatedBy    = "created_by"
+)
+
+// MaxPageSize is the largest Limit accepted on list inputs. A Limit of zero leaves the page size
+// to the service.
+const MaxPageSize = 1000
+
+// Sort orders for the SortOrder field of list inputs. SortBy accepts FieldCreated,
+// FieldModified, FieldStatus or FieldRangeKey; the backend default order is used when unset.
+const (
+	SortAscending  = "asc"
+	SortDescending = "desc"
+)
+
 // RetryPolicy defines the retry behavior for failed requests
 type RetryPolicy struct {
 	MaxRetries     int           // Maximum number of retries
 	InitialBackoff time.Duration // Initial backoff duration
 	MaxBackoff     time.Duration // Maximum backoff duration
 	BackoffFactor  float64       // Multiplication factor for backoff duration after each retry
-	Jitter         float64       // Jitter factor (0-1) to randomize backoff duration
+	Jitter         float64       // Jitter factor (0-1) for JitterProportional: each backoff is lengthened by a random fraction up to this
+
+	// JitterStrategy selects how backoffs are randomized; the zero value is JitterProportional
+	JitterStrategy JitterStrategy
+
+	// FirstRetryDelay, if positive, replaces InitialBackoff for the first retry only, e.g. for a longer
+	// pause after a 429; later retries keep the InitialBackoff * BackoffFactor^n schedule. Jitter and
+	// MaxBackoff still apply, except that with JitterDecorrelated the first wait is exactly this.
+	FirstRetryDelay time.Duration
+
+	// RetryableStatusCodes are retried in addition to the statuses ShouldRetry accepts,
+	// e.g. 423 from a gateway that locks during maintenance
+	RetryableStatusCodes []int
+
+	// MaxElapsedTime, if positive, stops retrying once the next backoff would end more than this long
+	// after the first attempt started. It bounds when retries start, not attempts in flight: each
+	// attempt still runs up to the client timeout, so a call can take MaxElapsedTime plus one timeout.
+	// Use a context deadline for a hard bound on the whole call.
+	MaxElapsedTime time.Duration
 }
 
+// JitterStrategy selects how calculateBackoff randomizes the wait before each retry
+type JitterStrategy int
+
+// Jitter strategies for RetryPolicy.JitterStrategy
+const (
+	JitterProportional JitterStrategy = iota // Lengthen the backoff by a random fraction up to RetryPolicy.Jitter, within MaxBackoff
+	JitterNone                               // Wait exactly the backoff
+	JitterFull                               // Wait a random duration between zero and the backoff
+	JitterEqual                              // Wait half the backoff plus a random duration up to the other half
+
+	// JitterDecorrelated waits a random duration between InitialBackoff and three times the previous
+	// wait of the same call, capped at MaxBackoff. BackoffFactor is not used.
+	JitterDecorrelated
+)
+
 // DefaultRetryPolicy provides reasonable default values for retry
 var DefaultRetryPolicy = RetryPolicy{
 	MaxRetries:     3,
@@ -42,36 +141,179 @@ var DefaultRetryPolicy = RetryPolicy{
 	Jitter:         0.2,
 }
 
-// Client represents a GDPR service client
+// Client represents a GDPR service client. A Client is safe for concurrent use by multiple
+// goroutines and is meant to be created once and shared; its configuration is fixed by NewClient,
+// and the state it keeps between calls (random source, circuit breaker, cache, rate limiter) is
+// guarded by its own locks.
 type Client struct {
 	baseURL     string
 	apiKey      string
+	authHeader  string
+	userAgent   string
+	accept      string
+	basePath    string
 	httpClient  *http.Client
 	environment string
 	retryPolicy RetryPolicy
+
+	responseTransform        func([]byte) ([]byte, error)
+	returnExistingOnConflict bool
+	strictDecoding           bool
+	checkTransitions         bool
+	curlOnError              bool
+	logger                   Logger
+	batchConcurrency         int
+	metrics                  Metrics
+	tracer                   Tracer
+	breaker                  *circuitBreaker
+	requestInterceptors      []func(*http.Request) error
+	responseInterceptors     []func(*http.Response) error
+	maxResponseBytes         int64
+	dryRun                   func(*http.Request)
+	tokenProvider            func(ctx context.Context) (string, error)
+	signer                   *sigV4Signer
+	cache                    *responseCache
+	limiter                  *rateLimiter
+	retryBudget              *rateLimiter
+	auditSink                AuditSink
+	bodyLogLimit             int
+	bodyRedactor             func([]byte) []byte
+	endpoints                EndpointResolver
+	correlationID            func(ctx context.Context) string
+	defaultCreatedBy         string
+	fallbackAPIKeys          []string
+	clock                    Clock
+	stats                    *clientStats
+
+	randMu sync.Mutex
+	rand   *rand.Rand
+
+	closeOnce sync.Once
+	closed    chan struct{}
+
+	// configErr records an invalid option for NewClient to return
+	configErr error
+
+	// options are the options NewClient applied, replayed by Clone
+	options []ClientOption
 }
 
+// DefaultMaxResponseBytes is the largest response body the client reads unless WithMaxResponseBytes is used
+const DefaultMaxResponseBytes = 10 << 20
+
+// DefaultAuthHeader is the header that carries the API key unless WithAuthHeader is used
+const DefaultAuthHeader = "X-Api-Key"
+
+// Version is the version of this client library, reported in DefaultUserAgent
+const Version = "0.1.0"
+
+// DefaultUserAgent is the User-Agent sent unless WithUserAgent is used
+const DefaultUserAgent = "gdprclient-go/" + Version
+
+// DefaultBasePath is the path the GDPR service is mounted under unless WithBasePath is used
+const DefaultBasePath = "/gdpr"
+
 // ClientOption is a function that configures a Client
 type ClientOption func(*Client)
 
-// NewClient creates a new GDPR service client
-func NewClient(baseURL, apiKey string, options ...ClientOption) *Client {
+// NewClient creates a new GDPR service client. baseURL must be an absolute http or https URL;
+// trailing slashes are removed. An error wrapping ErrInvalidInput is returned for an invalid
+// baseURL or option value.
+func NewClient(baseURL, apiKey string, options ...ClientOption) (*Client, error) {
+	baseURL, err := normalizeBaseURL(baseURL)
+	if err != nil {
+		return nil, err
+	}
+
 	client := &Client{
-		baseURL: baseURL,
-		apiKey:  apiKey,
+		baseURL:    baseURL,
+		apiKey:     apiKey,
+		authHeader: DefaultAuthHeader,
+		userAgent:  DefaultUserAgent,
+		basePath:   DefaultBasePath,
+		endpoints:  QueryEndpoints,
 		httpClient: &http.Client{
 			Timeout: 10 * time.Second,
 		},
-		environment: "Prod", // Default to production
+		environment: EnvironmentProd, // Default to production
 		retryPolicy: DefaultRetryPolicy,
+		logger:      noopLogger{},
+
+		batchConcurrency: DefaultBatchConcurrency,
+		maxResponseBytes: DefaultMaxResponseBytes,
+		clock:            realClock{},
+		stats:            &clientStats{},
+		correlationID:    CorrelationIDFromContext,
+		closed:           make(chan struct{}),
+		rand:             rand.New(rand.NewSource(time.Now().UnixNano())),
 	}
 
 	// Apply options
 	for _, option := range options {
 		option(client)
 	}
+	if client.configErr != nil {
+		return nil, client.configErr
+	}
+	client.options = append([]ClientOption(nil), options...)
+
+	return client, nil
+}
+
+// Clone returns a new Client with this client's configuration plus options, which are applied
+// after the original ones and so override them, e.g. WithAPIKey for a per-tenant client. The
+// clone has its own cache, circuit breaker, rate limiter, retry budget, Stats counters and random
+// source, seeded from this client's, so neither client's state affects the other. The HTTP
+// transport is shared.
+func (c *Client) Clone(options ...ClientOption) (*Client, error) {
+	all := make([]ClientOption, 0, len(c.options)+len(options))
+	all = append(all, c.options...)
+	all = append(all, options...)
+
+	clone, err := NewClient(c.baseURL, c.apiKey, all...)
+	if err != nil {
+		return nil, err
+	}
 
-	return client
+	// A source passed to WithRandSource must not be shared by two clients
+	c.randMu.Lock()
+	clone.rand = rand.New(rand.NewSource(c.rand.Int63()))
+	c.randMu.Unlock()
+
+	return clone, nil
+}
+
+// WithAPIKey sets the API key sent with every call, replacing the one given to NewClient
+func WithAPIKey(apiKey string) ClientOption {
+	return func(c *Client) {
+		c.apiKey = apiKey
+	}
+}
+
+// WithFallbackAPIKeys sets API keys to try, in order, when the service rejects a call with 401 or
+// 403, e.g. the next key during a rotation. Each key is tried once per call after the client's API
+// key; a Metrics that implements APIKeyObserver is told which key succeeded. Calls with an ApiKey
+// in their input use only that key.
+func WithFallbackAPIKeys(keys []string) ClientOption {
+	return func(c *Client) {
+		c.fallbackAPIKeys = append([]string(nil), keys...)
+	}
+}
+
+// normalizeBaseURL checks that baseURL is an absolute http or https URL and trims trailing slashes
+func normalizeBaseURL(baseURL string) (string, error) {
+	parsed, err := url.Parse(baseURL)
+	if err != nil {
+		return "", fmt.Errorf("%w: invalid base URL %q: %v", ErrInvalidInput, baseURL, err)
+	}
+	if (parsed.Scheme != "http" && parsed.Scheme != "https") || parsed.Host == "" {
+		return "", fmt.Errorf("%w: base URL must be an absolute http or https URL, got %q", ErrInvalidInput, baseURL)
+	}
+	if parsed.RawQuery != "" || parsed.Fragment != "" {
+		return "", fmt.Errorf("%w: base URL must not have a query or fragment, got %q", ErrInvalidInput, baseURL)
+	}
+
+	return strings.TrimRight(baseURL, "/"), nil
 }
 
 // WithTimeout sets the HTTP client timeout
@@ -81,9 +323,38 @@ func WithTimeout(timeout time.Duration) ClientOption {
 	}
 }
 
-// WithEnvironment sets the environment
+// WithDefaultCreatedBy sets the CreatedBy used by CreateInfoRequest and CreateDeleteRequest when
+// the input leaves it empty, e.g. a service account name. A CreatedBy set on the input wins.
+func WithDefaultCreatedBy(createdBy string) ClientOption {
+	return func(c *Client) {
+		c.defaultCreatedBy = createdBy
+	}
+}
+
+// WithHTTPClient replaces the HTTP client used to send requests, keeping its transport, proxy, TLS
+// and timeout settings; retries, interceptors and the other client features still apply on top.
+// The client is copied, so WithTimeout and WithTransport given after it change the copy rather than
+// the caller's client, and options are applied in order, so a later option wins. The transport is
+// shared with the caller, and Close releases its idle connections.
+func WithHTTPClient(httpClient *http.Client) ClientOption {
+	return func(c *Client) {
+		if httpClient == nil {
+			return
+		}
+		copied := *httpClient
+		c.httpClient = &copied
+	}
+}
+
+// WithEnvironment sets the environment sent in EnvironmentHeader. It must be one of
+// EnvironmentProd, EnvironmentStaging or EnvironmentDev; any other value makes NewClient
+// fail with an error wrapping ErrInvalidInput.
 func WithEnvironment(env string) ClientOption {
 	return func(c *Client) {
+		if err := oneOf("environment", env, validEnvironments, true); err != nil {
+			c.configErr = err
+			return
+		}
 		c.environment = env
 	}
 }
@@ -95,6 +366,52 @@ func WithTransport(transport http.RoundTripper) ClientOption {
 	}
 }
 
+// WithAuthHeader sets the name of the header used to send the API key, e.g. "Authorization"
+func WithAuthHeader(name string) ClientOption {
+	return func(c *Client) {
+		c.authHeader = name
+	}
+}
+
+// WithUserAgent sets the User-Agent sent with every request, e.g. "billing-service/2.3"
+func WithUserAgent(userAgent string) ClientOption {
+	return func(c *Client) {
+		c.userAgent = userAgent
+	}
+}
+
+// WithBasePath sets the path the GDPR service is mounted under, e.g. "/api/v2/gdpr"
+func WithBasePath(path string) ClientOption {
+	return func(c *Client) {
+		c.basePath = "/" + strings.Trim(path, "/")
+	}
+}
+
+// WithMaxResponseBytes sets the largest response body the client will read; larger responses fail with ErrResponseTooLarge
+func WithMaxResponseBytes(limit int64) ClientOption {
+	return func(c *Client) {
+		c.maxResponseBytes = limit
+	}
+}
+
+// WithDryRun stops the client from contacting the service. Each request is built as usual, including
+// headers, and passed to inspect instead of being sent; a synthetic empty 200 response is returned.
+func WithDryRun(inspect func(*http.Request)) ClientOption {
+	return func(c *Client) {
+		c.dryRun = inspect
+	}
+}
+
+// WithTokenProvider sets a function called before every attempt to obtain a bearer token, which is
+// sent as "Authorization: Bearer <token>". It can be combined with an API key or used with an empty
+// one; if the API key also uses the Authorization header, the token takes precedence.
+// A provider error fails the call before anything is sent.
+func WithTokenProvider(provider func(ctx context.Context) (string, error)) ClientOption {
+	return func(c *Client) {
+		c.tokenProvider = provider
+	}
+}
+
 // WithRetryPolicy sets a custom retry policy
 func WithRetryPolicy(policy RetryPolicy) ClientOption {
 	return func(c *Client) {
@@ -102,6 +419,13 @@ func WithRetryPolicy(policy RetryPolicy) ClientOption {
 	}
 }
 
+// WithRandSource sets the random source used for backoff jitter, so tests can make it deterministic
+func WithRandSource(source rand.Source) ClientOption {
+	return func(c *Client) {
+		c.rand = rand.New(source)
+	}
+}
+
 // WithMaxRetries sets the maximum number of retries
 func WithMaxRetries(maxRetries int) ClientOption {
 	return func(c *Client) {
@@ -109,6 +433,48 @@ func WithMaxRetries(maxRetries int) ClientOption {
 	}
 }
 
+// WithResponseTransform sets a function that rewrites the raw response body before it is parsed.
+// This allows backends with a different envelope shape to be mapped onto Response.
+func WithResponseTransform(transform func([]byte) ([]byte, error)) ClientOption {
+	return func(c *Client) {
+		c.responseTransform = transform
+	}
+}
+
+// WithExistingOnConflict makes create methods return the existing record together with an
+// *AlreadyExistsError (matching ErrAlreadyExists) when the service responds with 409
+func WithExistingOnConflict() ClientOption {
+	return func(c *Client) {
+		c.returnExistingOnConflict = true
+	}
+}
+
+// WithCurlOnError logs an equivalent curl command through the Logger at info level whenever a
+// request fails, including a 200 response whose envelope carries an error. The command is the
+// attempt as sent, with interceptor, token, signature and correlation headers, and with
+// credentials redacted.
+func WithCurlOnError() ClientOption {
+	return func(c *Client) {
+		c.curlOnError = true
+	}
+}
+
+// WithRequestInterceptor adds a function that runs on every attempt just before it is sent,
+// e.g. to add headers. Returning an error aborts the call without sending the attempt.
+func WithRequestInterceptor(interceptor func(*http.Request) error) ClientOption {
+	return func(c *Client) {
+		c.requestInterceptors = append(c.requestInterceptors, interceptor)
+	}
+}
+
+// WithResponseInterceptor adds a function that runs on every attempt's response as soon as it
+// is received, before retry decisions are made. Returning an error aborts the call.
+func WithResponseInterceptor(interceptor func(*http.Response) error) ClientOption {
+	return func(c *Client) {
+		c.responseInterceptors = append(c.responseInterceptors, interceptor)
+	}
+}
+
 // Response is the generic response structure
 type Response struct {
 	StatusCode int         `json:"statusCode"`
@@ -116,6 +482,13 @@ type Response struct {
 	Data       interface{} `json:"data,omitempty"`
 }
 
+// rawResponse is Response with Data left undecoded so it can be unmarshaled into its final type directly
+type rawResponse struct {
+	StatusCode int             `json:"statusCode"`
+	Message    string          `json:"message,omitempty"`
+	Data       json.RawMessage `json:"data,omitempty"`
+}
+
 // InfoRequest represents a data info request
 type InfoRequest struct {
 	PartitionKey string `json:"partition_key"`
@@ -138,12 +511,96 @@ type DeleteRequest struct {
 	CreatedBy    string `json:"created_by"`
 }
 
+// Clone returns a deep copy of r, or nil if r is nil. All fields are values today, so this is a
+// plain copy; callers that reuse a prototype should still Clone it so later reference fields
+// are not shared.
+func (r *InfoRequest) Clone() *InfoRequest {
+	if r == nil {
+		return nil
+	}
+	clone := *r
+	return &clone
+}
+
+// Clone returns a deep copy of r, or nil if r is nil, as InfoRequest.Clone does
+func (r *DeleteRequest) Clone() *DeleteRequest {
+	if r == nil {
+		return nil
+	}
+	clone := *r
+	return &clone
+}
+
+// CreatedAt parses Created, which the service sends in RFC 3339 format. An empty Created
+// gives the zero time.
+func (r InfoRequest) CreatedAt() (time.Time, error) {
+	return parseTimestamp(FieldCreated, r.Created)
+}
+
+// ModifiedAt parses Modified as CreatedAt does Created
+func (r InfoRequest) ModifiedAt() (time.Time, error) {
+	return parseTimestamp(FieldModified, r.Modified)
+}
+
+// CreatedAt parses Created as InfoRequest.CreatedAt does
+func (r DeleteRequest) CreatedAt() (time.Time, error) {
+	return parseTimestamp(FieldCreated, r.Created)
+}
+
+// ModifiedAt parses Modified as InfoRequest.CreatedAt does Created
+func (r DeleteRequest) ModifiedAt() (time.Time, error) {
+	return parseTimestamp(FieldModified, r.Modified)
+}
+
+// parseTimestamp parses an RFC 3339 timestamp, returning the zero time for an empty value
+func parseTimestamp(field, value string) (time.Time, error) {
+	if value == "" {
+		return time.Time{}, nil
+	}
+	t, err := time.Parse(time.RFC3339, value)
+	if err != nil {
+		return time.Time{}, fmt.Errorf("failed to parse %s: %v", field, err)
+	}
+	return t, nil
+}
+
+// field returns the value of the field with the given JSON name, used for client-side sorting
+func (r InfoRequest) field(name string) string {
+	return recordField(name, r.PartitionKey, r.RangeKey, r.Type, r.Status, r.Created, r.Modified, r.CreatedBy)
+}
+
+// field returns the value of the field with the given JSON name, used for client-side sorting
+func (r DeleteRequest) field(name string) string {
+	return recordField(name, r.PartitionKey, r.RangeKey, r.Type, r.Status, r.Created, r.Modified, r.CreatedBy)
+}
+
+// recordField picks the value matching a field name from the common request fields
+func recordField(name, partitionKey, rangeKey, requestType, status, created, modified, createdBy string) string {
+	switch name {
+	case FieldPartitionKey:
+		return partitionKey
+	case FieldRangeKey:
+		return rangeKey
+	case FieldType:
+		return requestType
+	case FieldStatus:
+		return status
+	case FieldCreated:
+		return created
+	case FieldModified:
+		return modified
+	case FieldCreatedBy:
+		return createdBy
+	}
+	return ""
+}
+
 // CreateInfoRequestInput is the input for creating an info request
 type CreateInfoRequestInput struct {
 	PartitionKey string `json:"partition_key"`
 	Type         string `json:"type"`
 	CreatedBy    string `json:"created_by"`
-	ApiKey       string `json:"api_key,omitempty"`
+	ApiKey       string `json:"-"`
 }
 
 // CreateDeleteRequestInput is the input for creating a deletion request
@@ -151,14 +608,16 @@ type CreateDeleteRequestInput struct {
 	PartitionKey string `json:"partition_key"`
 	Type         string `json:"type"`
 	CreatedBy    string `json:"created_by"`
-	ApiKey       string `json:"api_key,omitempty"`
+	ApiKey       string `json:"-"`
 }
 
 // FetchRequestInput is the input for fetching a request
 type FetchRequestInput struct {
 	PartitionKey string `json:"partition_key"`
 	RangeKey     string `json:"range_key"`
-	ApiKey       string `json:"api_key,omitempty"`
+	ApiKey       string `json:"-"`
+
+	ifNoneMatch string // ETag of a cached copy, set by the cache when revalidating
 }
 
 // UpdateRequestInput is the input for updating a request
@@ -167,22 +626,38 @@ type UpdateRequestInput struct {
 	RangeKey     string `json:"range_key"`
 	Type         string `json:"type,omitempty"`
 	Status       string `json:"status,omitempty"`
-	ApiKey       string `json:"api_key,omitempty"`
+	IfMatch      string `json:"-"` // Modified timestamp the caller last read; the update fails with ErrConflict if it has changed
+	ApiKey       string `json:"-"`
 }
 
-// TODO March 24, 2025 Correct the camelcase and make them underscore
-
 // ShouldRetry determines if a request should be retried based on the status code and error
 func ShouldRetry(statusCode int, err error) bool {
 	// Retry on network errors
 	if err != nil {
-		// Check for timeout, connection refused, or other temporary network errors
-		if errors.Is(err, context.DeadlineExceeded) ||
-			errors.Is(err, context.Canceled) ||
-			err.Error() == "connection refused" ||
-			err.Error() == "no such host" {
+		// Retry DNS failures only when the resolver says they are transient;
+		// a host that genuinely does not exist will not appear on retry
+		var dnsErr *net.DNSError
+		if errors.As(err, &dnsErr) {
+			return !dnsErr.IsNotFound && (dnsErr.IsTemporary || dnsErr.IsTimeout)
+		}
+
+		// Timeouts, including the http.Client timeout, are worth another attempt
+		var netErr net.Error
+		if errors.As(err, &netErr) && netErr.Timeout() {
+			return true
+		}
+
+		if errors.Is(err, syscall.ECONNREFUSED) || errors.Is(err, syscall.ECONNRESET) {
 			return true
 		}
+
+		// A failed dial never reached the service, so it is always safe to retry
+		var opErr *net.OpError
+		if errors.As(err, &opErr) && opErr.Op == "dial" {
+			return true
+		}
+
+		return false
 	}
 
 	// Retry on 5xx server errors, except 501 Not Implemented
@@ -198,15 +673,38 @@ func ShouldRetry(statusCode int, err error) bool {
 	return false
 }
 
-// calculateBackoff determines the backoff duration for a retry attempt
-func (c *Client) calculateBackoff(attempt int) time.Duration {
+// isRetryableStatus reports whether statusCode is one of the policy's RetryableStatusCodes
+func (p RetryPolicy) isRetryableStatus(statusCode int) bool {
+	for _, code := range p.RetryableStatusCodes {
+		if statusCode == code {
+			return true
+		}
+	}
+	return false
+}
+
+// calculateBackoff determines the backoff duration for a retry attempt. The exponential backoff is
+// capped at MaxBackoff and then randomized by the JitterStrategy, so waits stay within MaxBackoff.
+// Proportional jitter lengthens a backoff below the cap, up to the cap, and shortens one at the cap,
+// so waits remain randomized once the cap is reached. previous is the last wait of the same call,
+// or zero before the first retry; only JitterDecorrelated uses it.
+func (c *Client) calculateBackoff(attempt int, previous time.Duration) time.Duration {
+	first := attempt == 0 && c.retryPolicy.FirstRetryDelay > 0
+
+	if c.retryPolicy.JitterStrategy == JitterDecorrelated {
+		if first && c.retryPolicy.FirstRetryDelay > c.retryPolicy.MaxBackoff {
+			return c.retryPolicy.MaxBackoff
+		}
+		if first {
+			return c.retryPolicy.FirstRetryDelay
+		}
+		return c.decorrelatedBackoff(previous)
+	}
+
 	// Calculate base backoff with exponential increase
 	backoff := float64(c.retryPolicy.InitialBackoff) * math.Pow(c.retryPolicy.BackoffFactor, float64(attempt))
-
-	// Apply jitter
-	if c.retryPolicy.Jitter > 0 {
-		jitter := rand.Float64() * c.retryPolicy.Jitter
-		backoff = backoff * (1 + jitter)
+	if first {
+		backoff = float64(c.retryPolicy.FirstRetryDelay)
 	}
 
 	// Cap at max backoff
@@ -214,789 +712,1115 @@ func (c *Client) calculateBackoff(attempt int) time.Duration {
 		backoff = float64(c.retryPolicy.MaxBackoff)
 	}
 
+	// Apply jitter within the capped range
+	switch c.retryPolicy.JitterStrategy {
+	case JitterNone:
+	case JitterFull:
+		backoff = backoff * c.randFloat64()
+	case JitterEqual:
+		backoff = backoff/2 + backoff/2*c.randFloat64()
+	default:
+		if c.retryPolicy.Jitter > 0 {
+			jitter := c.retryPolicy.Jitter
+			if jitter > 1 {
+				jitter = 1
+			}
+			maxBackoff := float64(c.retryPolicy.MaxBackoff)
+			if backoff < maxBackoff {
+				backoff = math.Min(backoff*(1+c.randFloat64()*jitter), maxBackoff)
+			} else {
+				backoff = backoff * (1 - c.randFloat64()*jitter)
+			}
+		}
+	}
+
+	return time.Duration(backoff)
+}
+
+// decorrelatedBackoff returns min(MaxBackoff, random(InitialBackoff, previous*3)), treating a
+// previous wait below InitialBackoff as InitialBackoff
+func (c *Client) decorrelatedBackoff(previous time.Duration) time.Duration {
+	base := float64(c.retryPolicy.InitialBackoff)
+	if float64(previous) < base {
+		previous = c.retryPolicy.InitialBackoff
+	}
+
+	backoff := base + c.randFloat64()*(3*float64(previous)-base)
+	if backoff > float64(c.retryPolicy.MaxBackoff) {
+		backoff = float64(c.retryPolicy.MaxBackoff)
+	}
 	return time.Duration(backoff)
 }
 
-// doRequestWithRetry performs an HTTP request with retries according to the retry policy
-func (c *Client) doRequestWithRetry(req *http.Request) (*http.Response, error) {
+// parseRetryAfter parses a Retry-After header in either delta-seconds or HTTP-date form
+func parseRetryAfter(value string, now time.Time) (time.Duration, bool) {
+	value = strings.TrimSpace(value)
+	if value == "" {
+		return 0, false
+	}
+
+	if seconds, err := strconv.Atoi(value); err == nil {
+		if seconds < 0 {
+			return 0, false
+		}
+		return time.Duration(seconds) * time.Second, true
+	}
+
+	if date, err := http.ParseTime(value); err == nil {
+		if wait := date.Sub(now); wait > 0 {
+			return wait, true
+		}
+		return 0, true
+	}
+
+	return 0, false
+}
+
+// Close releases idle keep-alive connections and stops in-progress WaitForStatus polling.
+// Calls made after Close fail with ErrClientClosed. Close is safe to call more than once.
+func (c *Client) Close() error {
+	c.closeOnce.Do(func() {
+		close(c.closed)
+		c.httpClient.CloseIdleConnections()
+	})
+	return nil
+}
+
+// randFloat64 returns a random number in [0, 1) from the client's source, which is not safe for concurrent use on its own
+func (c *Client) randFloat64() float64 {
+	c.randMu.Lock()
+	defer c.randMu.Unlock()
+	return c.rand.Float64()
+}
+
+// doRequestWithRetry performs an HTTP request with retries according to the retry policy.
+// operation names the controller action for metrics and tracing; see sendWithRetry for retryBodyRead.
+func (c *Client) doRequestWithRetry(req *http.Request, operation, partitionKey string, retryBodyRead bool) (*http.Response, error) {
+	ctx, span := c.startSpan(req.Context(), operation)
+	defer span.End()
+	span.SetAttribute("gdpr.operation", operation)
+	if partitionKey != "" {
+		span.SetAttribute("gdpr.partition_key_hash", hashPartitionKey(partitionKey))
+	}
+
+	select {
+	case <-c.closed:
+		return nil, ErrClientClosed
+	default:
+	}
+
+	if c.breaker != nil && !c.breaker.allow(c.clock.Now()) {
+		span.SetAttribute("error", ErrCircuitOpen.Error())
+		return nil, ErrCircuitOpen
+	}
+
+	start := c.clock.Now()
+	resp, attempts, backoffs, err := c.sendWithRetry(req.WithContext(ctx), retryBodyRead)
+
+	recordResponseMetadata(ctx, resp, attempts, backoffs)
+
+	statusCode := 0
+	if resp != nil {
+		statusCode = resp.StatusCode
+	}
+	span.SetAttribute("http.status_code", statusCode)
+	span.SetAttribute("gdpr.attempts", attempts)
+	if err != nil {
+		span.SetAttribute("error", err.Error())
+	}
+
+	c.recordBreaker(ctx, statusCode, err)
+
+	c.stats.retried(attempts)
+
+	if c.metrics != nil {
+		c.metrics.ObserveRequest(operation, statusCode, c.clock.Now().Sub(start), attempts)
+		if observer, ok := c.metrics.(BackoffObserver); ok && len(backoffs) > 0 {
+			observer.ObserveBackoffs(operation, backoffs)
+		}
+	}
+
+	return resp, err
+}
+
+// sendWithRetry performs an HTTP request with retries according to the retry policy
+// and returns the last response or error along with the number of attempts sent. With
+// retryBodyRead, the body of a final response is read into memory within the loop, and a read
+// that fails, e.g. because the connection dropped, is retried like a failed attempt; only set it
+// for requests that are safe to send twice.
+func (c *Client) sendWithRetry(req *http.Request, retryBodyRead bool) (*http.Response, int, []time.Duration, error) {
 	var resp *http.Response
 	var err error
+	attempts := 0
+	start := c.clock.Now()
+	var backoff time.Duration
+	var backoffs []time.Duration
+	sent := req // the last attempt as sent, for logCurl
 
 	for attempt := 0; attempt <= c.retryPolicy.MaxRetries; attempt++ {
+		// Stop before building another attempt if the caller has given up
+		if ctxErr := req.Context().Err(); ctxErr != nil {
+			c.logCurl(sent)
+			return nil, attempts, backoffs, ctxErr
+		}
+
+		if c.limiter != nil {
+			if err := c.limiter.wait(req.Context(), c.clock); err != nil {
+				return nil, attempts, backoffs, err
+			}
+		}
+
 		// Clone the request to make it reusable for retries
 		reqClone := req.Clone(req.Context())
 
+		// The clone shares the original body, which an earlier attempt has already consumed
+		if req.GetBody != nil {
+			body, err := req.GetBody()
+			if err != nil {
+				return nil, attempts, backoffs, fmt.Errorf("failed to rewind request body: %v", err)
+			}
+			reqClone.Body = body
+		}
+
 		// If this is a retry, add a header indicating the retry attempt
 		if attempt > 0 {
 			reqClone.Header.Set("X-Retry-Attempt", fmt.Sprintf("%d", attempt))
 		}
 
-		resp, err = c.httpClient.Do(reqClone)
+		if c.tokenProvider != nil {
+			token, err := c.tokenProvider(req.Context())
+			if err != nil {
+				return nil, attempts, backoffs, fmt.Errorf("failed to get bearer token: %w", err)
+			}
+			reqClone.Header.Set("Authorization", "Bearer "+token)
+		}
+
+		for _, interceptor := range c.requestInterceptors {
+			if err := interceptor(reqClone); err != nil {
+				return nil, attempts, backoffs, fmt.Errorf("request interceptor failed: %w", err)
+			}
+		}
+
+		// Sign last, once every header is final, and again on each attempt for a fresh timestamp
+		if c.signer != nil {
+			if err := c.signer.sign(reqClone, c.clock.Now()); err != nil {
+				return nil, attempts, backoffs, err
+			}
+		}
+
+		c.logger.Debugf("gdprclient: sending %s %s (attempt %d)", req.Method, req.URL, attempt+1)
+		sent = reqClone
+		attempts++
+		attemptCtx, attemptSpan := c.startSpan(req.Context(), "attempt")
+		attemptSpan.SetAttribute("gdpr.attempt", attempt+1)
+		resp, err = c.roundTrip(reqClone.WithContext(attemptCtx))
+		if resp != nil {
+			attemptSpan.SetAttribute("http.status_code", resp.StatusCode)
+		}
+		if err != nil {
+			attemptSpan.SetAttribute("error", err.Error())
+		}
+		attemptSpan.End()
+
+		// Keep the attempt on the response so an error found while decoding can be logged with logCurl
+		if resp != nil && resp.Request == nil {
+			resp.Request = reqClone
+		}
+
+		if err == nil {
+			for _, interceptor := range c.responseInterceptors {
+				if err := interceptor(resp); err != nil {
+					resp.Body.Close()
+					return nil, attempts, backoffs, fmt.Errorf("response interceptor failed: %w", err)
+				}
+			}
+		}
+
+		final := err == nil && (resp.StatusCode < 500 && resp.StatusCode != 429) && !c.retryPolicy.isRetryableStatus(resp.StatusCode)
+		if final && retryBodyRead {
+			err = c.bufferResponseBody(resp)
+		}
 
 		// If no error and successful status code, return the response
-		if err == nil && (resp.StatusCode < 500 && resp.StatusCode != 429) {
-			return resp, nil
+		if final && err == nil {
+			if resp.StatusCode >= 400 {
+				c.logCurl(sent)
+			}
+			return resp, attempts, backoffs, nil
 		}
 
 		// Check if we should retry
 		statusCode := 0
 		if resp != nil {
 			statusCode = resp.StatusCode
-			// Make sure to close the response body before retrying
-			resp.Body.Close()
 		}
 
-		if !ShouldRetry(statusCode, err) || attempt >= c.retryPolicy.MaxRetries {
+		retryable := ShouldRetry(statusCode, err) || c.retryPolicy.isRetryableStatus(statusCode) || errors.Is(err, errBodyRead)
+		if !retryable || attempt >= c.retryPolicy.MaxRetries {
+			c.logger.Infof("gdprclient: %s failed after %d attempts (status %d, error: %v)", req.URL, attempt+1, statusCode, err)
 			break
 		}
 
-		// Calculate backoff duration and wait
-		backoff := c.calculateBackoff(attempt)
-		time.Sleep(backoff)
-	}
+		// Calculate backoff duration and wait, giving up early if the caller cancels
+		backoff = c.calculateBackoff(attempt, backoff)
+
+		// Respect the server's throttle guidance on 429, within MaxBackoff. The guidance only sets
+		// this wait; backoff stays the computed value, which JitterDecorrelated builds the next on.
+		wait := backoff
+		if statusCode == http.StatusTooManyRequests {
+			if retryAfter, ok := parseRetryAfter(resp.Header.Get("Retry-After"), c.clock.Now()); ok && retryAfter > wait {
+				wait = retryAfter
+				if wait > c.retryPolicy.MaxBackoff {
+					wait = c.retryPolicy.MaxBackoff
+				}
+			}
+		}
 
-	// Return the last response or error
-	return resp, err
-}
+		if c.retryPolicy.MaxElapsedTime > 0 && c.clock.Now().Sub(start)+wait > c.retryPolicy.MaxElapsedTime {
+			c.logger.Infof("gdprclient: %s failed after %d attempts, max elapsed time reached (status %d, error: %v)", req.URL, attempt+1, statusCode, err)
+			break
+		}
 
-// FetchAllRequestInput is the input for fetching all requests
-type FetchAllRequestInput struct {
-	PartitionKey string `json:"partitionKey"`
-	LastRangeKey string `json:"lastRangeKey,omitempty"`
-	ApiKey       string `json:"apiKey,omitempty"`
-}
+		if c.retryBudget != nil && !c.retryBudget.allow(c.clock.Now()) {
+			c.logger.Infof("gdprclient: %s failed after %d attempts, retry budget exhausted (status %d, error: %v)", req.URL, attempt+1, statusCode, err)
+			break
+		}
 
-// FetchByTypeInput is the input for fetching requests by type
-type FetchByTypeInput struct {
-	Type         string `json:"type"`
-	LastRangeKey string `json:"lastRangeKey,omitempty"`
-	ApiKey       string `json:"apiKey,omitempty"`
-}
+		// Make sure to close the response body before retrying
+		if resp != nil {
+			resp.Body.Close()
+		}
 
-// FetchByStatusInput is the input for fetching requests by status
-type FetchByStatusInput struct {
-	Status       string `json:"status"`
-	LastRangeKey string `json:"lastRangeKey,omitempty"`
-	ApiKey       string `json:"apiKey,omitempty"`
-}
+		c.logger.Debugf("gdprclient: retrying %s in %v (status %d, error: %v)", req.URL, wait, statusCode, err)
+		waitStart := c.clock.Now()
+		timer := c.clock.NewTimer(wait)
+		select {
+		case <-timer.C():
+			backoffs = append(backoffs, wait)
+		case <-req.Context().Done():
+			// Stop the timer so a cancelled call does not leave it running until the backoff ends
+			timer.Stop()
+			backoffs = append(backoffs, c.clock.Now().Sub(waitStart))
+			c.logCurl(sent)
+			return nil, attempts, backoffs, req.Context().Err()
+		}
+	}
 
-// FetchByCreatorInput is the input for fetching requests by creator
-type FetchByCreatorInput struct {
-	CreatedBy    string `json:"createdBy"`
-	LastRangeKey string `json:"lastRangeKey,omitempty"`
-	ApiKey       string `json:"apiKey,omitempty"`
-}
+	c.logCurl(sent)
 
-// DeleteRequestInput is the input for deleting a request
-type DeleteRequestInput struct {
-	PartitionKey string `json:"partitionKey"`
-	RangeKey     string `json:"rangeKey"`
-	IsHardDelete bool   `json:"isHardDelete"`
-	ApiKey       string `json:"apiKey,omitempty"`
+	// Return the last response or error
+	return resp, attempts, backoffs, err
 }
 
-// PaginatedResponse is a response containing paginated results
-type PaginatedResponse struct {
-	Results      []interface{} `json:"results"`
-	LastRangeKey string        `json:"lastRangeKey,omitempty"`
+// roundTrip sends a single attempt, or hands it to the dry run inspector when dry run is enabled
+func (c *Client) roundTrip(req *http.Request) (*http.Response, error) {
+	if c.dryRun == nil {
+		return c.httpClient.Do(req)
+	}
+
+	c.dryRun(req)
+
+	body := `{"statusCode":200,"message":"dry run"}`
+	return &http.Response{
+		Status:        "200 OK",
+		StatusCode:    http.StatusOK,
+		Proto:         "HTTP/1.1",
+		ProtoMajor:    1,
+		ProtoMinor:    1,
+		Header:        http.Header{"Content-Type": []string{"application/json"}},
+		Body:          io.NopCloser(strings.NewReader(body)),
+		ContentLength: int64(len(body)),
+		Request:       req,
+	}, nil
 }
 
-// CreateInfoRequest creates a new info request
-func (c *Client) CreateInfoRequest(input CreateInfoRequestInput) (*InfoRequest, error) {
-	// Use client's API key if not provided in input
-	if input.ApiKey == "" {
-		input.ApiKey = c.apiKey
-	}
-
-	body, err := json.Marshal(input)
-	if err != nil {
-		return nil, fmt.Errorf("failed to marshal request body: %v", err)
-	}
-
-	req, err := http.NewRequest("POST", fmt.Sprintf("%s/gdpr?action=create", c.baseURL), bytes.NewBuffer(body))
-	if err != nil {
-		return nil, fmt.Errorf("failed to create request: %v", err)
-	}
+// errBodyRead is wrapped by errors from reading a response body after the response arrived
+var errBodyRead = errors.New("failed to read response body")
 
-	req.Header.Set("Content-Type", "application/json")
-
-	resp, err := c.doRequestWithRetry(req)
-	if err != nil {
-		return nil, fmt.Errorf("failed to send request: %v", err)
-	}
+// bufferResponseBody reads the response body, up to one byte past the size limit so
+// readResponseBody can still report ErrResponseTooLarge, and replaces it with the bytes read.
+// The original body is closed either way.
+func (c *Client) bufferResponseBody(resp *http.Response) error {
 	defer resp.Body.Close()
 
-	responseBody, err := io.ReadAll(resp.Body)
+	body, err := io.ReadAll(io.LimitReader(resp.Body, c.maxResponseBytes+1))
 	if err != nil {
-		return nil, fmt.Errorf("failed to read response body: %v", err)
-	}
-
-	if resp.StatusCode != http.StatusOK {
-		return nil, fmt.Errorf("request failed with status %d: %s", resp.StatusCode, string(responseBody))
+		return fmt.Errorf("%w: %v", errBodyRead, err)
 	}
-
-	if resp.StatusCode != 200 {
-		return nil, fmt.Errorf("GDPR service returned error: %s, StatusCode: %v", resp.Body, resp.StatusCode)
-	}
-
-	// Convert response.Data to InfoRequest
-	dataJSON, err := json.Marshal(resp.Body)
-	if err != nil {
-		return nil, fmt.Errorf("failed to marshal data: %v", err)
-	}
-
-	var infoRequest InfoRequest
-	if jsonErr := json.Unmarshal(dataJSON, &infoRequest); err != nil {
-		return nil, fmt.Errorf("failed to unmarshal data: %v", jsonErr)
-	}
-
-	return &infoRequest, nil
+	resp.Body = io.NopCloser(bytes.NewReader(body))
+	return nil
 }
 
-// CreateDeleteRequest creates a new deletion request
-func (c *Client) CreateDeleteRequest(input CreateDeleteRequestInput) (*DeleteRequest, error) {
-	// Use client's API key if not provided in input
-	if input.ApiKey == "" {
-		input.ApiKey = c.apiKey
+// readResponseBody reads the full response body and applies the response transform, if any
+func (c *Client) readResponseBody(resp *http.Response) ([]byte, error) {
+	body, err := io.ReadAll(io.LimitReader(resp.Body, c.maxResponseBytes+1))
+	if err != nil {
+		return nil, fmt.Errorf("%w: %v", errBodyRead, err)
 	}
 
-	body, err := json.Marshal(input)
-	if err != nil {
-		return nil, fmt.Errorf("failed to marshal request body: %v", err)
+	if int64(len(body)) > c.maxResponseBytes {
+		return nil, fmt.Errorf("%w: limit is %d bytes", ErrResponseTooLarge, c.maxResponseBytes)
 	}
 
-	req, err := http.NewRequest("POST", fmt.Sprintf("%s/gdpr?controller=delete&action=create", c.baseURL), bytes.NewBuffer(body))
-	if err != nil {
-		return nil, fmt.Errorf("failed to create request: %v", err)
+	if c.responseTransform != nil {
+		body, err = c.responseTransform(body)
+		if err != nil {
+			return nil, fmt.Errorf("failed to transform response body: %v", err)
+		}
 	}
 
-	req.Header.Set("Content-Type", "application/json")
+	return body, nil
+}
 
-	resp, err := c.doRequestWithRetry(req)
-	if err != nil {
-		return nil, fmt.Errorf("failed to send request: %v", err)
-	}
-	defer resp.Body.Close()
+// FetchAllRequestInput is the input for fetching all requests
+type FetchAllRequestInput struct {
+	PartitionKey string   `json:"partition_key"`
+	LastRangeKey string   `json:"last_range_key,omitempty"`
+	Fields       []string `json:"fields,omitempty"`
+	SortBy       string   `json:"sort_by,omitempty"`
+	SortOrder    string   `json:"sort_order,omitempty"`
+	Limit        int      `json:"limit,omitempty"`
+	ApiKey       string   `json:"-"`
+}
 
-	responseBody, err := io.ReadAll(resp.Body)
-	if err != nil {
-		return nil, fmt.Errorf("failed to read response body: %v", err)
-	}
+// FetchByTypeInput is the input for fetching requests by type
+type FetchByTypeInput struct {
+	Type         string   `json:"type"`
+	LastRangeKey string   `json:"last_range_key,omitempty"`
+	Fields       []string `json:"fields,omitempty"`
+	SortBy       string   `json:"sort_by,omitempty"`
+	SortOrder    string   `json:"sort_order,omitempty"`
+	Limit        int      `json:"limit,omitempty"`
+	ApiKey       string   `json:"-"`
+}
 
-	if resp.StatusCode != http.StatusOK {
-		return nil, fmt.Errorf("request failed with status %d: %s", resp.StatusCode, string(responseBody))
-	}
+// FetchByStatusInput is the input for fetching requests by status
+type FetchByStatusInput struct {
+	Status       string   `json:"status"`
+	LastRangeKey string   `json:"last_range_key,omitempty"`
+	Fields       []string `json:"fields,omitempty"`
+	SortBy       string   `json:"sort_by,omitempty"`
+	SortOrder    string   `json:"sort_order,omitempty"`
+	Limit        int      `json:"limit,omitempty"`
+	ApiKey       string   `json:"-"`
+}
 
-	var response Response
-	if err := json.Unmarshal(responseBody, &response); err != nil {
-		return nil, fmt.Errorf("failed to unmarshal response: %v", err)
-	}
+// FetchByCreatorInput is the input for fetching requests by creator
+type FetchByCreatorInput struct {
+	CreatedBy    string   `json:"created_by"`
+	LastRangeKey string   `json:"last_range_key,omitempty"`
+	Fields       []string `json:"fields,omitempty"`
+	SortBy       string   `json:"sort_by,omitempty"`
+	SortOrder    string   `json:"sort_order,omitempty"`
+	Limit        int      `json:"limit,omitempty"`
+	ApiKey       string   `json:"-"`
+}
 
-	if response.StatusCode != 200 {
-		return nil, fmt.Errorf("GDPR service returned error: %s", response.Message)
-	}
+// FetchByDateRangeInput is the input for fetching requests created in [From, To)
+type FetchByDateRangeInput struct {
+	From         time.Time `json:"from"`
+	To           time.Time `json:"to"`
+	LastRangeKey string    `json:"last_range_key,omitempty"`
+	Fields       []string  `json:"fields,omitempty"`
+	SortBy       string    `json:"sort_by,omitempty"`
+	SortOrder    string    `json:"sort_order,omitempty"`
+	Limit        int       `json:"limit,omitempty"`
+	ApiKey       string    `json:"-"`
+}
 
-	// Convert response.Data to DeleteRequest
-	dataJSON, err := json.Marshal(response.Data)
-	if err != nil {
-		return nil, fmt.Errorf("failed to marshal data: %v", err)
-	}
+// DeleteRequestInput is the input for deleting a request
+type DeleteRequestInput struct {
+	PartitionKey string `json:"partition_key"`
+	RangeKey     string `json:"range_key"`
+	IsHardDelete bool   `json:"is_hard_delete"`
+	ApiKey       string `json:"-"`
+}
 
-	var deleteRequest DeleteRequest
-	if err := json.Unmarshal(dataJSON, &deleteRequest); err != nil {
-		return nil, fmt.Errorf("failed to unmarshal data: %v", err)
-	}
+// PaginatedResponse is a response containing paginated results. Total and HasMore are only set
+// when the backend reports them.
+//
+// LastRangeKey is a cursor: passing it as the LastRangeKey of the next input for the same action
+// and filter continues right after this page, including from a new client after a restart. The
+// pagers' Cursor and Resume methods wrap it in a token that also records the action.
+type PaginatedResponse struct {
+	Results      []interface{} `json:"results"`
+	LastRangeKey string        `json:"lastRangeKey,omitempty"`
+	Total        *int          `json:"total,omitempty"`
+	HasMore      *bool         `json:"hasMore,omitempty"`
+}
 
-	return &deleteRequest, nil
+// HasNextPage reports whether another page follows this one
+func (p *PaginatedResponse) HasNextPage() bool {
+	return hasNextPage(p.LastRangeKey, p.HasMore)
 }
 
-// FetchInfoRequest fetches an info request by ID
-func (c *Client) FetchInfoRequest(input FetchRequestInput) (*InfoRequest, error) {
-	// Use client's API key if not provided in input
-	if input.ApiKey == "" {
-		input.ApiKey = c.apiKey
+// hasNextPage decides whether to fetch another page. An explicit HasMore from the backend wins,
+// since some backends return the last cursor on the final page; without a cursor there is no
+// next page to ask for either way.
+func hasNextPage(lastRangeKey string, hasMore *bool) bool {
+	if lastRangeKey == "" {
+		return false
 	}
+	return hasMore == nil || *hasMore
+}
 
-	body, err := json.Marshal(input)
+// Decode converts the page results into out, which must be a pointer to a slice such as
+// *[]InfoRequest or a slice of a custom struct for actions called with Do
+func (p *PaginatedResponse) Decode(out interface{}) error {
+	dataJSON, err := json.Marshal(p.Results)
 	if err != nil {
-		return nil, fmt.Errorf("failed to marshal request body: %v", err)
+		return fmt.Errorf("failed to marshal results: %v", err)
 	}
 
-	req, err := http.NewRequest("POST", fmt.Sprintf("%s/gdpr?action=fetch", c.baseURL), bytes.NewBuffer(body))
-	if err != nil {
-		return nil, fmt.Errorf("failed to create request: %v", err)
+	if err := json.Unmarshal(dataJSON, out); err != nil {
+		return fmt.Errorf("failed to unmarshal results: %v", err)
 	}
 
-	req.Header.Set("Content-Type", "application/json")
+	return nil
+}
 
-	resp, err := c.doRequestWithRetry(req)
-	if err != nil {
-		return nil, fmt.Errorf("failed to send request: %v", err)
-	}
-	defer resp.Body.Close()
+// PaginatedInfoResponse is a page of info requests; see PaginatedResponse for LastRangeKey
+type PaginatedInfoResponse struct {
+	Results      []InfoRequest `json:"results"`
+	LastRangeKey string        `json:"lastRangeKey,omitempty"`
+	Total        *int          `json:"total,omitempty"`
+	HasMore      *bool         `json:"hasMore,omitempty"`
+}
 
-	responseBody, err := io.ReadAll(resp.Body)
-	if err != nil {
-		return nil, fmt.Errorf("failed to read response body: %v", err)
-	}
+// HasNextPage reports whether another page follows this one
+func (p *PaginatedInfoResponse) HasNextPage() bool {
+	return hasNextPage(p.LastRangeKey, p.HasMore)
+}
 
-	if resp.StatusCode != http.StatusOK {
-		return nil, fmt.Errorf("request failed with status %d: %s", resp.StatusCode, string(responseBody))
-	}
+// SortResults orders the results of this page by a field using SortAscending or SortDescending.
+// This only sorts the materialized page and compares values as strings; prefer the SortBy input
+// field so the backend orders across pages, as sorting client-side requires loading every page.
+func (p *PaginatedInfoResponse) SortResults(field, order string) {
+	sort.SliceStable(p.Results, func(i, j int) bool {
+		if order == SortDescending {
+			return p.Results[i].field(field) > p.Results[j].field(field)
+		}
+		return p.Results[i].field(field) < p.Results[j].field(field)
+	})
+}
 
-	var response Response
-	if err := json.Unmarshal(responseBody, &response); err != nil {
-		return nil, fmt.Errorf("failed to unmarshal response: %v", err)
-	}
+// PaginatedDeleteResponse is a page of delete requests; see PaginatedResponse for LastRangeKey
+type PaginatedDeleteResponse struct {
+	Results      []DeleteRequest `json:"results"`
+	LastRangeKey string          `json:"lastRangeKey,omitempty"`
+	Total        *int            `json:"total,omitempty"`
+	HasMore      *bool           `json:"hasMore,omitempty"`
+}
 
-	if response.StatusCode == 404 {
-		return nil, errors.New("info request not found")
-	}
+// HasNextPage reports whether another page follows this one
+func (p *PaginatedDeleteResponse) HasNextPage() bool {
+	return hasNextPage(p.LastRangeKey, p.HasMore)
+}
 
-	if response.StatusCode != 200 {
-		return nil, fmt.Errorf("GDPR service returned error: %s", response.Message)
-	}
+// SortResults orders the results of this page by a field using SortAscending or SortDescending.
+// This only sorts the materialized page and compares values as strings; prefer the SortBy input
+// field so the backend orders across pages, as sorting client-side requires loading every page.
+func (p *PaginatedDeleteResponse) SortResults(field, order string) {
+	sort.SliceStable(p.Results, func(i, j int) bool {
+		if order == SortDescending {
+			return p.Results[i].field(field) > p.Results[j].field(field)
+		}
+		return p.Results[i].field(field) < p.Results[j].field(field)
+	})
+}
 
-	// Convert response.Data to InfoRequest
-	dataJSON, err := json.Marshal(response.Data)
-	if err != nil {
-		return nil, fmt.Errorf("failed to marshal data: %v", err)
+// CreateInfoRequest creates a new info request
+func (c *Client) CreateInfoRequest(ctx context.Context, input CreateInfoRequestInput) (*InfoRequest, error) {
+	if input.CreatedBy == "" {
+		input.CreatedBy = c.defaultCreatedBy
+	}
+	if err := input.Validate(); err != nil {
+		return nil, err
 	}
 
 	var infoRequest InfoRequest
-	if err := json.Unmarshal(dataJSON, &infoRequest); err != nil {
-		return nil, fmt.Errorf("failed to unmarshal data: %v", err)
+	if err := c.create(ctx, "", input.ApiKey, input, &infoRequest); err != nil {
+		var exists *AlreadyExistsError
+		if errors.As(err, &exists) {
+			return &infoRequest, err
+		}
+		return nil, err
 	}
 
+	c.audit("", "create", infoRequest.PartitionKey, infoRequest.RangeKey)
 	return &infoRequest, nil
 }
 
-// FetchDeleteRequest fetches a delete request by ID
-func (c *Client) FetchDeleteRequest(input FetchRequestInput) (*DeleteRequest, error) {
-	// Use client's API key if not provided in input
-	if input.ApiKey == "" {
-		input.ApiKey = c.apiKey
+// CreateDeleteRequest creates a new deletion request
+func (c *Client) CreateDeleteRequest(ctx context.Context, input CreateDeleteRequestInput) (*DeleteRequest, error) {
+	if input.CreatedBy == "" {
+		input.CreatedBy = c.defaultCreatedBy
 	}
-
-	body, err := json.Marshal(input)
-	if err != nil {
-		return nil, fmt.Errorf("failed to marshal request body: %v", err)
+	if err := input.Validate(); err != nil {
+		return nil, err
 	}
 
-	req, err := http.NewRequest("POST", fmt.Sprintf("%s/gdpr?controller=delete&action=fetch", c.baseURL), bytes.NewBuffer(body))
-	if err != nil {
-		return nil, fmt.Errorf("failed to create request: %v", err)
+	var deleteRequest DeleteRequest
+	if err := c.create(ctx, "delete", input.ApiKey, input, &deleteRequest); err != nil {
+		var exists *AlreadyExistsError
+		if errors.As(err, &exists) {
+			return &deleteRequest, err
+		}
+		return nil, err
 	}
 
-	req.Header.Set("Content-Type", "application/json")
-
-	resp, err := c.httpClient.Do(req)
-	if err != nil {
-		return nil, fmt.Errorf("failed to send request: %v", err)
-	}
-	defer resp.Body.Close()
+	c.audit("delete", "create", deleteRequest.PartitionKey, deleteRequest.RangeKey)
+	return &deleteRequest, nil
+}
 
-	responseBody, err := io.ReadAll(resp.Body)
-	if err != nil {
-		return nil, fmt.Errorf("failed to read response body: %v", err)
+// create sends a create action and decodes the new record into out. With WithExistingOnConflict,
+// a 409 decodes the existing record into out instead and returns an *AlreadyExistsError.
+func (c *Client) create(ctx context.Context, controller, apiKey string, input, out interface{}) error {
+	statusCode, responseBody, err := c.post(ctx, controller, "create", apiKey, input)
+	if err == nil {
+		err = c.decodeCreated(controller, statusCode, responseBody, out)
 	}
+	c.stats.record(err)
+	return err
+}
 
-	if resp.StatusCode != http.StatusOK {
-		return nil, fmt.Errorf("request failed with status %d: %s", resp.StatusCode, string(responseBody))
+// decodeCreated decodes the response of a create action into out
+func (c *Client) decodeCreated(controller string, statusCode int, responseBody []byte, out interface{}) error {
+	if c.returnExistingOnConflict {
+		if conflict, err := decodeConflict(statusCode, responseBody, out); conflict {
+			if err != nil {
+				return err
+			}
+			return &AlreadyExistsError{Record: out}
+		}
 	}
+	return c.decodeResponse(controller, "create", statusCode, responseBody, out)
+}
 
-	var response Response
-	if err := json.Unmarshal(responseBody, &response); err != nil {
-		return nil, fmt.Errorf("failed to unmarshal response: %v", err)
+// FetchInfoRequest fetches an info request by ID
+func (c *Client) FetchInfoRequest(ctx context.Context, input FetchRequestInput) (*InfoRequest, error) {
+	if err := input.Validate(); err != nil {
+		return nil, err
 	}
 
-	if response.StatusCode == 404 {
-		return nil, errors.New("delete request not found")
+	var infoRequest InfoRequest
+	if err := c.fetchCached(ctx, "", input, &infoRequest); err != nil {
+		if IsNotFound(err) {
+			return nil, fmt.Errorf("info request not found: %w", err)
+		}
+		return nil, err
 	}
 
-	if response.StatusCode != 200 {
-		return nil, fmt.Errorf("GDPR service returned error: %s", response.Message)
-	}
+	return &infoRequest, nil
+}
 
-	// Convert response.Data to DeleteRequest
-	dataJSON, err := json.Marshal(response.Data)
-	if err != nil {
-		return nil, fmt.Errorf("failed to marshal data: %v", err)
+// FetchDeleteRequest fetches a delete request by ID
+func (c *Client) FetchDeleteRequest(ctx context.Context, input FetchRequestInput) (*DeleteRequest, error) {
+	if err := input.Validate(); err != nil {
+		return nil, err
 	}
 
 	var deleteRequest DeleteRequest
-	if err := json.Unmarshal(dataJSON, &deleteRequest); err != nil {
-		return nil, fmt.Errorf("failed to unmarshal data: %v", err)
+	if err := c.fetchCached(ctx, "delete", input, &deleteRequest); err != nil {
+		if IsNotFound(err) {
+			return nil, fmt.Errorf("delete request not found: %w", err)
+		}
+		return nil, err
 	}
 
 	return &deleteRequest, nil
 }
 
-// UpdateInfoRequest updates an info request
-func (c *Client) UpdateInfoRequest(input UpdateRequestInput) (bool, error) {
-	// Use client's API key if not provided in input
-	if input.ApiKey == "" {
-		input.ApiKey = c.apiKey
-	}
+// Request is the result of FetchRequest. Type is TypeInfoRequest or TypeDeleteRequest
+// and exactly one of Info or Delete is set to match it.
+type Request struct {
+	Type   string
+	Info   *InfoRequest
+	Delete *DeleteRequest
+}
 
-	body, err := json.Marshal(input)
-	if err != nil {
-		return false, fmt.Errorf("failed to marshal request body: %v", err)
+// Clone returns a deep copy of r, including the InfoRequest or DeleteRequest it points to,
+// or nil if r is nil
+func (r *Request) Clone() *Request {
+	if r == nil {
+		return nil
 	}
+	return &Request{Type: r.Type, Info: r.Info.Clone(), Delete: r.Delete.Clone()}
+}
 
-	req, err := http.NewRequest("POST", fmt.Sprintf("%s/gdpr?action=update", c.baseURL), bytes.NewBuffer(body))
-	if err != nil {
-		return false, fmt.Errorf("failed to create request: %v", err)
+// FetchRequest fetches a request by key without knowing whether it is an info or delete request.
+// The info controller is tried first, then the delete controller if the request was not found.
+func (c *Client) FetchRequest(ctx context.Context, input FetchRequestInput) (*Request, error) {
+	if err := input.Validate(); err != nil {
+		return nil, err
 	}
 
-	req.Header.Set("Content-Type", "application/json")
-
-	resp, err := c.doRequestWithRetry(req)
-	if err != nil {
-		return false, fmt.Errorf("failed to send request: %v", err)
+	var lastErr error
+	for _, controller := range []string{"", "delete"} {
+		var data json.RawMessage
+		err := c.do(ctx, controller, "fetch", input.ApiKey, input, &data)
+		if IsNotFound(err) {
+			lastErr = err
+			continue
+		}
+		if err != nil {
+			return nil, err
+		}
+		return c.decodeRequest(data)
 	}
-	defer resp.Body.Close()
 
-	responseBody, err := io.ReadAll(resp.Body)
-	if err != nil {
-		return false, fmt.Errorf("failed to read response body: %v", err)
-	}
+	return nil, fmt.Errorf("request not found: %w", lastErr)
+}
 
-	if resp.StatusCode != http.StatusOK {
-		return false, fmt.Errorf("request failed with status %d: %s", resp.StatusCode, string(responseBody))
+// decodeRequest decodes request data into the struct matching its type field
+func (c *Client) decodeRequest(data json.RawMessage) (*Request, error) {
+	var header struct {
+		Type string `json:"type"`
 	}
-
-	var response Response
-	if err := json.Unmarshal(responseBody, &response); err != nil {
-		return false, fmt.Errorf("failed to unmarshal response: %v", err)
+	if err := json.Unmarshal(data, &header); err != nil {
+		return nil, fmt.Errorf("failed to unmarshal data: %v", err)
 	}
 
-	if response.StatusCode != 200 {
-		return false, fmt.Errorf("GDPR service returned error: %s", response.Message)
+	request := &Request{Type: header.Type}
+	switch header.Type {
+	case TypeInfoRequest:
+		request.Info = &InfoRequest{}
+		if err := c.decodeData(data, request.Info); err != nil {
+			return nil, err
+		}
+	case TypeDeleteRequest:
+		request.Delete = &DeleteRequest{}
+		if err := c.decodeData(data, request.Delete); err != nil {
+			return nil, err
+		}
+	default:
+		return nil, fmt.Errorf("unknown request type %q", header.Type)
 	}
 
-	return true, nil
+	return request, nil
 }
 
-// UpdateDeleteRequest updates a delete request
-func (c *Client) UpdateDeleteRequest(input UpdateRequestInput) (bool, error) {
-	// Use client's API key if not provided in input
-	if input.ApiKey == "" {
-		input.ApiKey = c.apiKey
-	}
-
-	body, err := json.Marshal(input)
-	if err != nil {
-		return false, fmt.Errorf("failed to marshal request body: %v", err)
-	}
-
-	req, err := http.NewRequest("POST", fmt.Sprintf("%s/gdpr?controller=delete&action=update", c.baseURL), bytes.NewBuffer(body))
-	if err != nil {
-		return false, fmt.Errorf("failed to create request: %v", err)
+// UpdateInfoRequest updates an info request and returns the record as updated by the service.
+// A success response without the record fails with ErrNoRecord, except in dry-run mode, where
+// the zero InfoRequest is returned.
+// With WithStatusTransitionCheck, a Status change that may not follow the current status
+// returns ErrInvalidTransition without updating.
+func (c *Client) UpdateInfoRequest(ctx context.Context, input UpdateRequestInput) (*InfoRequest, error) {
+	if err := input.Validate(); err != nil {
+		return nil, err
 	}
-
-	req.Header.Set("Content-Type", "application/json")
-
-	resp, err := c.httpClient.Do(req)
-	if err != nil {
-		return false, fmt.Errorf("failed to send request: %v", err)
+	if err := c.checkTransition(ctx, "", input); err != nil {
+		return nil, err
 	}
-	defer resp.Body.Close()
 
-	responseBody, err := io.ReadAll(resp.Body)
+	var data json.RawMessage
+	err := c.do(ctx, "", "update", input.ApiKey, input, &data)
+	c.cache.invalidate(cacheKey("", input.PartitionKey, input.RangeKey))
 	if err != nil {
-		return false, fmt.Errorf("failed to read response body: %v", err)
+		return nil, err
 	}
 
-	if resp.StatusCode != http.StatusOK {
-		return false, fmt.Errorf("request failed with status %d: %s", resp.StatusCode, string(responseBody))
-	}
-
-	var response Response
-	if err := json.Unmarshal(responseBody, &response); err != nil {
-		return false, fmt.Errorf("failed to unmarshal response: %v", err)
-	}
-
-	if response.StatusCode != 200 {
-		return false, fmt.Errorf("GDPR service returned error: %s", response.Message)
+	var infoRequest InfoRequest
+	if err := c.decodeRecord("", "update", data, &infoRequest); err != nil {
+		return nil, err
 	}
 
-	return true, nil
+	c.audit("", "update", input.PartitionKey, input.RangeKey)
+	return &infoRequest, nil
 }
 
-// DeleteRequest deletes a request (info or delete)
-func (c *Client) DeleteInfoRequest(input DeleteRequestInput) (bool, error) {
-	// Use client's API key if not provided in input
-	if input.ApiKey == "" {
-		input.ApiKey = c.apiKey
-	}
-
-	body, err := json.Marshal(input)
-	if err != nil {
-		return false, fmt.Errorf("failed to marshal request body: %v", err)
+// UpdateDeleteRequest updates a delete request and returns the record as updated by the service.
+// A success response without the record fails with ErrNoRecord, except in dry-run mode, where
+// the zero DeleteRequest is returned.
+// With WithStatusTransitionCheck, a Status change that may not follow the current status
+// returns ErrInvalidTransition without updating.
+func (c *Client) UpdateDeleteRequest(ctx context.Context, input UpdateRequestInput) (*DeleteRequest, error) {
+	if err := input.Validate(); err != nil {
+		return nil, err
 	}
-
-	req, err := http.NewRequest("POST", fmt.Sprintf("%s/gdpr?action=delete", c.baseURL), bytes.NewBuffer(body))
-	if err != nil {
-		return false, fmt.Errorf("failed to create request: %v", err)
+	if err := c.checkTransition(ctx, "delete", input); err != nil {
+		return nil, err
 	}
 
-	req.Header.Set("Content-Type", "application/json")
-
-	resp, err := c.doRequestWithRetry(req)
+	var data json.RawMessage
+	err := c.do(ctx, "delete", "update", input.ApiKey, input, &data)
+	c.cache.invalidate(cacheKey("delete", input.PartitionKey, input.RangeKey))
 	if err != nil {
-		return false, fmt.Errorf("failed to send request: %v", err)
+		return nil, err
 	}
-	defer resp.Body.Close()
 
-	responseBody, err := io.ReadAll(resp.Body)
-	if err != nil {
-		return false, fmt.Errorf("failed to read response body: %v", err)
+	var deleteRequest DeleteRequest
+	if err := c.decodeRecord("delete", "update", data, &deleteRequest); err != nil {
+		return nil, err
 	}
 
-	if resp.StatusCode != http.StatusOK {
-		return false, fmt.Errorf("request failed with status %d: %s", resp.StatusCode, string(responseBody))
-	}
+	c.audit("delete", "update", input.PartitionKey, input.RangeKey)
+	return &deleteRequest, nil
+}
 
-	var response Response
-	if err := json.Unmarshal(responseBody, &response); err != nil {
-		return false, fmt.Errorf("failed to unmarshal response: %v", err)
+// DeleteRequest deletes a request (info or delete)
+func (c *Client) DeleteInfoRequest(ctx context.Context, input DeleteRequestInput) (bool, error) {
+	if err := input.Validate(); err != nil {
+		return false, err
 	}
 
-	if response.StatusCode != 200 {
-		return false, fmt.Errorf("GDPR service returned error: %s", response.Message)
+	err := c.do(ctx, "", "delete", input.ApiKey, input, nil)
+	c.cache.invalidate(cacheKey("", input.PartitionKey, input.RangeKey))
+	if err != nil {
+		return false, err
 	}
 
+	c.audit("", "delete", input.PartitionKey, input.RangeKey)
 	return true, nil
 }
 
 // DeleteRequest deletes a request (info or delete)
-func (c *Client) DeleteRequest(input DeleteRequestInput) (bool, error) {
-	// Use client's API key if not provided in input
-	if input.ApiKey == "" {
-		input.ApiKey = c.apiKey
-	}
-
-	body, err := json.Marshal(input)
-	if err != nil {
-		return false, fmt.Errorf("failed to marshal request body: %v", err)
+func (c *Client) DeleteRequest(ctx context.Context, input DeleteRequestInput) (bool, error) {
+	if err := input.Validate(); err != nil {
+		return false, err
 	}
 
-	req, err := http.NewRequest("POST", fmt.Sprintf("%s/gdpr?controller=delete&action=delete", c.baseURL), bytes.NewBuffer(body))
+	err := c.do(ctx, "delete", "delete", input.ApiKey, input, nil)
+	c.cache.invalidate(cacheKey("delete", input.PartitionKey, input.RangeKey))
 	if err != nil {
-		return false, fmt.Errorf("failed to create request: %v", err)
+		return false, err
 	}
 
-	req.Header.Set("Content-Type", "application/json")
+	c.audit("delete", "delete", input.PartitionKey, input.RangeKey)
+	return true, nil
+}
 
-	resp, err := c.doRequestWithRetry(req)
-	if err != nil {
-		return false, fmt.Errorf("failed to send request: %v", err)
-	}
-	defer resp.Body.Close()
+// restoreRequestInput is the body of a restore action
+type restoreRequestInput struct {
+	PartitionKey string `json:"partition_key"`
+	RangeKey     string `json:"range_key"`
+}
 
-	responseBody, err := io.ReadAll(resp.Body)
-	if err != nil {
-		return false, fmt.Errorf("failed to read response body: %v", err)
-	}
+// RestoreInfoRequest un-deletes an info request that was soft deleted with DeleteInfoRequest.
+// A request that was hard deleted is gone, so restoring it returns an error matching
+// ErrRequestNotFound.
+func (c *Client) RestoreInfoRequest(ctx context.Context, partitionKey, rangeKey string) (bool, error) {
+	return c.restore(ctx, "", partitionKey, rangeKey)
+}
 
-	if resp.StatusCode != http.StatusOK {
-		return false, fmt.Errorf("request failed with status %d: %s", resp.StatusCode, string(responseBody))
-	}
+// RestoreRequest un-deletes a delete request that was soft deleted with DeleteRequest, as
+// RestoreInfoRequest does for info requests
+func (c *Client) RestoreRequest(ctx context.Context, partitionKey, rangeKey string) (bool, error) {
+	return c.restore(ctx, "delete", partitionKey, rangeKey)
+}
 
-	var response Response
-	if err := json.Unmarshal(responseBody, &response); err != nil {
-		return false, fmt.Errorf("failed to unmarshal response: %v", err)
+// restore calls the restore action of a controller
+func (c *Client) restore(ctx context.Context, controller, partitionKey, rangeKey string) (bool, error) {
+	if err := firstError(
+		required(FieldPartitionKey, partitionKey),
+		required(FieldRangeKey, rangeKey),
+	); err != nil {
+		return false, err
 	}
 
-	if response.StatusCode != 200 {
-		return false, fmt.Errorf("GDPR service returned error: %s", response.Message)
+	input := restoreRequestInput{PartitionKey: partitionKey, RangeKey: rangeKey}
+	err := c.do(ctx, controller, "restore", "", input, nil)
+	c.cache.invalidate(cacheKey(controller, partitionKey, rangeKey))
+	if err != nil {
+		return false, err
 	}
 
+	c.audit(controller, "restore", partitionKey, rangeKey)
 	return true, nil
 }
 
 // FetchAllInfoRequests fetches all info requests for a partition key
-func (c *Client) FetchAllInfoRequests(input FetchAllRequestInput) (*PaginatedResponse, error) {
-	// Use client's API key if not provided in input
-	if input.ApiKey == "" {
-		input.ApiKey = c.apiKey
-	}
-
-	body, err := json.Marshal(input)
-	if err != nil {
-		return nil, fmt.Errorf("failed to marshal request body: %v", err)
-	}
-
-	req, err := http.NewRequest("POST", fmt.Sprintf("%s/gdpr?action=fetchAll", c.baseURL), bytes.NewBuffer(body))
-	if err != nil {
-		return nil, fmt.Errorf("failed to create request: %v", err)
+func (c *Client) FetchAllInfoRequests(ctx context.Context, input FetchAllRequestInput) (*PaginatedInfoResponse, error) {
+	if err := input.Validate(); err != nil {
+		return nil, err
 	}
 
-	req.Header.Set("Content-Type", "application/json")
-
-	resp, err := c.doRequestWithRetry(req)
-	if err != nil {
-		return nil, fmt.Errorf("failed to send request: %v", err)
+	var paginatedResponse PaginatedInfoResponse
+	if err := c.do(ctx, "", "fetchAll", input.ApiKey, input, &paginatedResponse); err != nil {
+		return nil, err
 	}
-	defer resp.Body.Close()
 
-	responseBody, err := io.ReadAll(resp.Body)
-	if err != nil {
-		return nil, fmt.Errorf("failed to read response body: %v", err)
-	}
+	return &paginatedResponse, nil
+}
 
-	if resp.StatusCode != http.StatusOK {
-		return nil, fmt.Errorf("request failed with status %d: %s", resp.StatusCode, string(responseBody))
+// FetchInfoRequestsByType fetches info requests by type
+func (c *Client) FetchInfoRequestsByType(ctx context.Context, input FetchByTypeInput) (*PaginatedInfoResponse, error) {
+	if err := input.Validate(); err != nil {
+		return nil, err
 	}
 
-	var response Response
-	if err := json.Unmarshal(responseBody, &response); err != nil {
-		return nil, fmt.Errorf("failed to unmarshal response: %v", err)
+	var paginatedResponse PaginatedInfoResponse
+	if err := c.do(ctx, "", "fetchByType", input.ApiKey, input, &paginatedResponse); err != nil {
+		return nil, err
 	}
 
-	if response.StatusCode != 200 {
-		return nil, fmt.Errorf("GDPR service returned error: %s", response.Message)
-	}
+	return &paginatedResponse, nil
+}
 
-	// Convert response.Data to PaginatedResponse
-	dataJSON, err := json.Marshal(response.Data)
-	if err != nil {
-		return nil, fmt.Errorf("failed to marshal data: %v", err)
+// FetchInfoRequestsByStatus fetches info requests by status
+func (c *Client) FetchInfoRequestsByStatus(ctx context.Context, input FetchByStatusInput) (*PaginatedInfoResponse, error) {
+	if err := input.Validate(); err != nil {
+		return nil, err
 	}
 
-	var paginatedResponse PaginatedResponse
-	if err := json.Unmarshal(dataJSON, &paginatedResponse); err != nil {
-		return nil, fmt.Errorf("failed to unmarshal data: %v", err)
+	var paginatedResponse PaginatedInfoResponse
+	if err := c.do(ctx, "", "fetchByStatus", input.ApiKey, input, &paginatedResponse); err != nil {
+		return nil, err
 	}
 
 	return &paginatedResponse, nil
 }
 
-// FetchInfoRequestsByType fetches info requests by type
-func (c *Client) FetchInfoRequestsByType(input FetchByTypeInput) (*PaginatedResponse, error) {
-	// Use client's API key if not provided in input
-	if input.ApiKey == "" {
-		input.ApiKey = c.apiKey
-	}
-
-	body, err := json.Marshal(input)
-	if err != nil {
-		return nil, fmt.Errorf("failed to marshal request body: %v", err)
-	}
-
-	req, err := http.NewRequest("POST", fmt.Sprintf("%s/gdpr?action=fetchByType", c.baseURL), bytes.NewBuffer(body))
-	if err != nil {
-		return nil, fmt.Errorf("failed to create request: %v", err)
+// FetchAllDeleteRequests fetches all delete requests for a partition key
+func (c *Client) FetchAllDeleteRequests(ctx context.Context, input FetchAllRequestInput) (*PaginatedDeleteResponse, error) {
+	if err := input.Validate(); err != nil {
+		return nil, err
 	}
 
-	req.Header.Set("Content-Type", "application/json")
-
-	resp, err := c.doRequestWithRetry(req)
-	if err != nil {
-		return nil, fmt.Errorf("failed to send request: %v", err)
+	var paginatedResponse PaginatedDeleteResponse
+	if err := c.do(ctx, "delete", "fetchAll", input.ApiKey, input, &paginatedResponse); err != nil {
+		return nil, err
 	}
-	defer resp.Body.Close()
 
-	responseBody, err := io.ReadAll(resp.Body)
-	if err != nil {
-		return nil, fmt.Errorf("failed to read response body: %v", err)
-	}
+	return &paginatedResponse, nil
+}
 
-	if resp.StatusCode != http.StatusOK {
-		return nil, fmt.Errorf("request failed with status %d: %s", resp.StatusCode, string(responseBody))
+// FetchDeleteRequestsByStatus fetches delete requests by status
+func (c *Client) FetchDeleteRequestsByStatus(ctx context.Context, input FetchByStatusInput) (*PaginatedDeleteResponse, error) {
+	if err := input.Validate(); err != nil {
+		return nil, err
 	}
 
-	var response Response
-	if err := json.Unmarshal(responseBody, &response); err != nil {
-		return nil, fmt.Errorf("failed to unmarshal response: %v", err)
+	var paginatedResponse PaginatedDeleteResponse
+	if err := c.do(ctx, "delete", "fetchByStatus", input.ApiKey, input, &paginatedResponse); err != nil {
+		return nil, err
 	}
 
-	if response.StatusCode != 200 {
-		return nil, fmt.Errorf("GDPR service returned error: %s", response.Message)
-	}
+	return &paginatedResponse, nil
+}
 
-	// Convert response.Data to PaginatedResponse
-	dataJSON, err := json.Marshal(response.Data)
-	if err != nil {
-		return nil, fmt.Errorf("failed to marshal data: %v", err)
+// FetchInfoRequestsByCreator fetches info requests by creator
+func (c *Client) FetchInfoRequestsByCreator(ctx context.Context, input FetchByCreatorInput) (*PaginatedInfoResponse, error) {
+	if err := input.Validate(); err != nil {
+		return nil, err
 	}
 
-	var paginatedResponse PaginatedResponse
-	if err := json.Unmarshal(dataJSON, &paginatedResponse); err != nil {
-		return nil, fmt.Errorf("failed to unmarshal data: %v", err)
+	var paginatedResponse PaginatedInfoResponse
+	if err := c.do(ctx, "", "fetchByCreator", input.ApiKey, input, &paginatedResponse); err != nil {
+		return nil, err
 	}
 
 	return &paginatedResponse, nil
 }
 
-// FetchDeleteRequestsByStatus fetches delete requests by status
-func (c *Client) FetchDeleteRequestsByStatus(input FetchByStatusInput) (*PaginatedResponse, error) {
-	// Use client's API key if not provided in input
-	if input.ApiKey == "" {
-		input.ApiKey = c.apiKey
-	}
+// FetchRequestsByCreator fetches info requests by creator.
+//
+// Deprecated: use FetchInfoRequestsByCreator, or FetchDeleteRequestsByCreator for delete requests.
+func (c *Client) FetchRequestsByCreator(ctx context.Context, input FetchByCreatorInput) (*PaginatedInfoResponse, error) {
+	return c.FetchInfoRequestsByCreator(ctx, input)
+}
 
-	body, err := json.Marshal(input)
-	if err != nil {
-		return nil, fmt.Errorf("failed to marshal request body: %v", err)
+// FetchDeleteRequestsByCreator fetches delete requests by creator
+func (c *Client) FetchDeleteRequestsByCreator(ctx context.Context, input FetchByCreatorInput) (*PaginatedDeleteResponse, error) {
+	if err := input.Validate(); err != nil {
+		return nil, err
 	}
 
-	req, err := http.NewRequest("POST", fmt.Sprintf("%s/gdpr?controller=delete&action=fetchByStatus", c.baseURL), bytes.NewBuffer(body))
-	if err != nil {
-		return nil, fmt.Errorf("failed to create request: %v", err)
+	var paginatedResponse PaginatedDeleteResponse
+	if err := c.do(ctx, "delete", "fetchByCreator", input.ApiKey, input, &paginatedResponse); err != nil {
+		return nil, err
 	}
 
-	req.Header.Set("Content-Type", "application/json")
+	return &paginatedResponse, nil
+}
 
-	resp, err := c.doRequestWithRetry(req)
-	if err != nil {
-		return nil, fmt.Errorf("failed to send request: %v", err)
+// FetchInfoRequestsByDateRange fetches info requests created from input.From up to, but not
+// including, input.To
+func (c *Client) FetchInfoRequestsByDateRange(ctx context.Context, input FetchByDateRangeInput) (*PaginatedInfoResponse, error) {
+	if err := input.Validate(); err != nil {
+		return nil, err
 	}
-	defer resp.Body.Close()
 
-	responseBody, err := io.ReadAll(resp.Body)
-	if err != nil {
-		return nil, fmt.Errorf("failed to read response body: %v", err)
+	var paginatedResponse PaginatedInfoResponse
+	if err := c.do(ctx, "", "fetchByDateRange", input.ApiKey, input, &paginatedResponse); err != nil {
+		return nil, err
 	}
 
-	if resp.StatusCode != http.StatusOK {
-		return nil, fmt.Errorf("request failed with status %d: %s", resp.StatusCode, string(responseBody))
-	}
+	return &paginatedResponse, nil
+}
 
-	var response Response
-	if err := json.Unmarshal(responseBody, &response); err != nil {
-		return nil, fmt.Errorf("failed to unmarshal response: %v", err)
+// FetchDeleteRequestsByDateRange fetches delete requests created from input.From up to, but not
+// including, input.To
+func (c *Client) FetchDeleteRequestsByDateRange(ctx context.Context, input FetchByDateRangeInput) (*PaginatedDeleteResponse, error) {
+	if err := input.Validate(); err != nil {
+		return nil, err
 	}
 
-	if response.StatusCode != 200 {
-		return nil, fmt.Errorf("GDPR service returned error: %s", response.Message)
+	var paginatedResponse PaginatedDeleteResponse
+	if err := c.do(ctx, "delete", "fetchByDateRange", input.ApiKey, input, &paginatedResponse); err != nil {
+		return nil, err
 	}
 
-	// Convert response.Data to PaginatedResponse
-	dataJSON, err := json.Marshal(response.Data)
-	if err != nil {
-		return nil, fmt.Errorf("failed to marshal data: %v", err)
-	}
+	return &paginatedResponse, nil
+}
 
-	var paginatedResponse PaginatedResponse
-	if err := json.Unmarshal(dataJSON, &paginatedResponse); err != nil {
-		return nil, fmt.Errorf("failed to unmarshal data: %v", err)
+// Do calls an arbitrary controller action on the GDPR service. It sends body as JSON with the
+// client's API key, retry policy and response handling, and decodes response.Data into out.
+// An empty controller targets the info request controller. out may be nil to discard the data.
+func (c *Client) Do(ctx context.Context, controller, action string, body interface{}, out interface{}) error {
+	if body == nil {
+		body = struct{}{}
 	}
 
-	return &paginatedResponse, nil
+	return c.do(ctx, controller, action, "", body, out)
 }
 
-// FetchRequestsByCreator fetches requests by creator
-func (c *Client) FetchRequestsByCreator(input FetchByCreatorInput) (*PaginatedResponse, error) {
-	// Use client's API key if not provided in input
-	if input.ApiKey == "" {
-		input.ApiKey = c.apiKey
+// do sends body to a controller action and decodes response.Data into out, which may be nil.
+// An empty apiKey falls back to the client's API key.
+func (c *Client) do(ctx context.Context, controller, action, apiKey string, body interface{}, out interface{}) error {
+	statusCode, header, responseBody, err := c.postWithHeader(ctx, controller, action, apiKey, body)
+	if err == nil {
+		if statusCode == http.StatusOK && isCSV(header) {
+			err = c.decodeCSV(controller, action, header, responseBody, out)
+		} else {
+			err = c.decodeResponse(controller, action, statusCode, responseBody, out)
+		}
 	}
 
-	body, err := json.Marshal(input)
-	if err != nil {
-		return nil, fmt.Errorf("failed to marshal request body: %v", err)
-	}
+	c.stats.record(err)
+	return err
+}
 
-	req, err := http.NewRequest("POST", fmt.Sprintf("%s/gdpr?action=fetchByCreator", c.baseURL), bytes.NewBuffer(body))
-	if err != nil {
-		return nil, fmt.Errorf("failed to create request: %v", err)
+// decodeRecord decodes the record returned by a write into out. Data that is missing or null
+// fails with ErrNoRecord, unless the client is in dry-run mode and so never received a record.
+func (c *Client) decodeRecord(controller, action string, data json.RawMessage, out interface{}) error {
+	if len(bytes.TrimSpace(data)) == 0 || bytes.Equal(bytes.TrimSpace(data), []byte("null")) {
+		if c.dryRun != nil {
+			return nil
+		}
+		return fmt.Errorf("%w for %s", ErrNoRecord, operationName(controller, action))
 	}
+	return c.decodeData(data, out)
+}
 
-	req.Header.Set("Content-Type", "application/json")
+// post sends body as JSON to a controller action and returns the HTTP status code and response body.
+// An empty apiKey falls back to the client's API key. When the connection drops while the body of
+// an idempotent read is being received, the attempt is retried like any other failed attempt; writes
+// are never re-sent, since the service may already have applied them.
+func (c *Client) post(ctx context.Context, controller, action, apiKey string, body interface{}) (int, []byte, error) {
+	statusCode, _, responseBody, err := c.postWithHeader(ctx, controller, action, apiKey, body)
+	return statusCode, responseBody, err
+}
 
-	resp, err := c.doRequestWithRetry(req)
+// postWithHeader is post that also returns the response headers
+func (c *Client) postWithHeader(ctx context.Context, controller, action, apiKey string, body interface{}) (int, http.Header, []byte, error) {
+	resp, err := c.send(ctx, controller, action, apiKey, c.acceptFor(action), isIdempotent(action), body)
 	if err != nil {
-		return nil, fmt.Errorf("failed to send request: %v", err)
+		return 0, nil, nil, err
 	}
 	defer resp.Body.Close()
 
-	responseBody, err := io.ReadAll(resp.Body)
+	responseBody, err := c.readResponseBody(resp)
 	if err != nil {
-		return nil, fmt.Errorf("failed to read response body: %v", err)
+		return 0, nil, nil, err
 	}
+	c.logBody(operationName(controller, action), "response", responseBody)
+	c.logCurlOnEnvelopeError(resp, responseBody)
 
-	if resp.StatusCode != http.StatusOK {
-		return nil, fmt.Errorf("request failed with status %d: %s", resp.StatusCode, string(responseBody))
-	}
+	return resp.StatusCode, resp.Header, responseBody, nil
+}
 
-	var response Response
-	if err := json.Unmarshal(responseBody, &response); err != nil {
-		return nil, fmt.Errorf("failed to unmarshal response: %v", err)
-	}
+// isIdempotent reports whether an action only reads, so sending it twice is harmless
+func isIdempotent(action string) bool {
+	return strings.HasPrefix(action, "fetch")
+}
 
-	if response.StatusCode != 200 {
-		return nil, fmt.Errorf("GDPR service returned error: %s", response.Message)
+// send sends body as JSON to a controller action and returns the response with its body unread,
+// or with retryBodyRead, read into memory by the retry loop so a failed read is retried. The
+// caller must close the response body.
+func (c *Client) send(ctx context.Context, controller, action, apiKey, accept string, retryBodyRead bool, body interface{}) (*http.Response, error) {
+	payload, err := json.Marshal(body)
+	if err != nil {
+		return nil, fmt.Errorf("failed to marshal request body: %v", err)
 	}
+	c.logBody(operationName(controller, action), "request", payload)
 
-	// Convert response.Data to PaginatedResponse
-	dataJSON, err := json.Marshal(response.Data)
+	// From a *bytes.Reader, NewRequestWithContext sets ContentLength and GetBody, so every attempt
+	// sends Content-Length, which some proxies require, even for a body of just "{}"
+	method, target := c.endpoint(controller, action)
+	req, err := http.NewRequestWithContext(ctx, method, target, bytes.NewReader(payload))
 	if err != nil {
-		return nil, fmt.Errorf("failed to marshal data: %v", err)
+		return nil, fmt.Errorf("failed to create request: %v", err)
 	}
 
-	var paginatedResponse PaginatedResponse
-	if err := json.Unmarshal(dataJSON, &paginatedResponse); err != nil {
-		return nil, fmt.Errorf("failed to unmarshal data: %v", err)
+	req.Header.Set("Content-Type", MediaTypeJSON)
+	req.Header.Set("Accept", accept)
+	req.Header.Set(EnvironmentHeader, c.environment)
+	req.Header.Set("User-Agent", c.userAgent)
+	if id := c.correlationID(ctx); id != "" {
+		req.Header.Set(CorrelationIDHeader, id)
 	}
-
-	return &paginatedResponse, nil
-}
-
-// FetchRequestsByCreator fetches requests by creator
-func (c *Client) FetchDeleteRequestsByCreator(input FetchByCreatorInput) (*PaginatedResponse, error) {
-	// Use client's API key if not provided in input
-	if input.ApiKey == "" {
-		input.ApiKey = c.apiKey
+	if input, ok := body.(UpdateRequestInput); ok && input.IfMatch != "" {
+		req.Header.Set("If-Match", input.IfMatch)
 	}
-
-	body, err := json.Marshal(input)
-	if err != nil {
-		return nil, fmt.Errorf("failed to marshal request body: %v", err)
+	if input, ok := body.(FetchRequestInput); ok && input.ifNoneMatch != "" {
+		req.Header.Set("If-None-Match", input.ifNoneMatch)
 	}
 
-	req, err := http.NewRequest("POST", fmt.Sprintf("%s/gdpr?controller=delete&action=fetchByCreator", c.baseURL), bytes.NewBuffer(body))
-	if err != nil {
-		return nil, fmt.Errorf("failed to create request: %v", err)
+	// Use client's API key if not provided in input; only the client's key has fallbacks
+	keys := []string{apiKey}
+	if apiKey == "" {
+		keys = append([]string{c.apiKey}, c.fallbackAPIKeys...)
 	}
 
-	req.Header.Set("Content-Type", "application/json")
+	operation := operationName(controller, action)
+	for i := 0; ; i++ {
+		if key := keys[i]; key != "" {
+			req.Header.Set(c.authHeader, key)
+		}
 
-	resp, err := c.doRequestWithRetry(req)
-	if err != nil {
-		return nil, fmt.Errorf("failed to send request: %v", err)
-	}
-	defer resp.Body.Close()
+		resp, err := c.doRequestWithRetry(req, operation, partitionKeyOf(body), retryBodyRead)
+		if err != nil {
+			return nil, fmt.Errorf("failed to send request: %w", err)
+		}
 
-	responseBody, err := io.ReadAll(resp.Body)
-	if err != nil {
-		return nil, fmt.Errorf("failed to read response body: %v", err)
+		rejected := resp.StatusCode == http.StatusUnauthorized || resp.StatusCode == http.StatusForbidden
+		if !rejected || i == len(keys)-1 {
+			if !rejected && i > 0 {
+				c.observeAPIKeyFallback(operation, i)
+			}
+			return resp, nil
+		}
+
+		resp.Body.Close()
+		c.logger.Infof("gdprclient: %s was rejected with status %d, trying fallback API key %d", operation, resp.StatusCode, i+1)
 	}
+}
 
-	if resp.StatusCode != http.StatusOK {
-		return nil, fmt.Errorf("request failed with status %d: %s", resp.StatusCode, string(responseBody))
+// decodeResponse checks the HTTP status and response envelope, then decodes response.Data into out
+func (c *Client) decodeResponse(controller, action string, statusCode int, responseBody []byte, out interface{}) error {
+	if statusCode != http.StatusOK || !json.Valid(responseBody) {
+		return newAPIError(controller, action, statusCode, responseBody)
 	}
 
-	var response Response
+	var response rawResponse
 	if err := json.Unmarshal(responseBody, &response); err != nil {
-		return nil, fmt.Errorf("failed to unmarshal response: %v", err)
+		return fmt.Errorf("failed to unmarshal response: %v", err)
 	}
 
 	if response.StatusCode != 200 {
-		return nil, fmt.Errorf("GDPR service returned error: %s", response.Message)
-	}
-
-	// Convert response.Data to PaginatedResponse
-	dataJSON, err := json.Marshal(response.Data)
-	if err != nil {
-		return nil, fmt.Errorf("failed to marshal data: %v", err)
+		return &APIError{
+			StatusCode:     response.StatusCode,
+			ServiceMessage: response.Message,
+			Controller:     controller,
+			Action:         action,
+		}
 	}
 
-	var paginatedResponse PaginatedResponse
-	if err := json.Unmarshal(dataJSON, &paginatedResponse); err != nil {
-		return nil, fmt.Errorf("failed to unmarshal data: %v", err)
+	if out == nil {
+		return nil
 	}
 
-	return &paginatedResponse, nil
+	// Decode response.Data straight into the caller's type
+	return c.decodeData(response.Data, out)
 }
@@ -0,0 +1,460 @@
+// Package gdprclienttest provides an in-memory fake of the GDPR service for testing code
+// that uses gdprclient without a live backend.
+package gdprclienttest
+
+import (
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"net/http/httptest"
+	"sort"
+	"strings"
+	"sync"
+	"testing"
+	"time"
+
+	"github.com/cincinnatiai/gdprclient"
+)
+
+// APIKey is the key the fake server accepts unless WithAPIKey is used
+const APIKey = "test-api-key"
+
+// DefaultPageSize is the number of results per page returned by list actions
+const DefaultPageSize = 50
+
+// Server is an httptest server that implements the GDPR service actions against an in-memory store.
+// Fetches carry an ETag that changes whenever the record does, and a fetch whose If-None-Match
+// matches it gets 304 Not Modified.
+type Server struct {
+	*httptest.Server
+
+	mu            sync.Mutex
+	apiKey        string
+	authHeader    string
+	pathEndpoints bool
+	pageSize      int
+	nextID        int
+	nextVersion   int
+	stores        map[string]map[string]*record // controller -> "partition|range" -> record
+	versions      map[*record]int               // changes with every write to a record, for its ETag
+}
+
+// Option configures a Server
+type Option func(*Server)
+
+// WithAPIKey sets the API key the server requires in the X-Api-Key header
+func WithAPIKey(apiKey string) Option {
+	return func(s *Server) {
+		s.apiKey = apiKey
+	}
+}
+
+// WithAuthHeader sets the header the server reads the API key from, as gdprclient.WithAuthHeader
+// does for the client; Client configures its clients to match
+func WithAuthHeader(name string) Option {
+	return func(s *Server) {
+		s.authHeader = name
+	}
+}
+
+// WithPathEndpoints makes the server route actions by path, as sent with gdprclient.PathEndpoints,
+// instead of by query; Client configures its clients to match
+func WithPathEndpoints() Option {
+	return func(s *Server) {
+		s.pathEndpoints = true
+	}
+}
+
+// WithPageSize sets the number of results per page returned by list actions
+func WithPageSize(pageSize int) Option {
+	return func(s *Server) {
+		s.pageSize = pageSize
+	}
+}
+
+// record is a stored info or delete request
+type record struct {
+	PartitionKey string `json:"partition_key"`
+	RangeKey     string `json:"range_key,omitempty"`
+	Type         string `json:"type"`
+	Status       string `json:"status,omitempty"`
+	Created      string `json:"created,omitempty"`
+	Modified     string `json:"modified,omitempty"`
+	CreatedBy    string `json:"created_by"`
+}
+
+// requestBody accepts the fields sent by every client input
+type requestBody struct {
+	PartitionKey string    `json:"partition_key"`
+	RangeKey     string    `json:"range_key"`
+	Type         string    `json:"type"`
+	Status       string    `json:"status"`
+	CreatedBy    string    `json:"created_by"`
+	LastRangeKey string    `json:"last_range_key"`
+	From         time.Time `json:"from"`
+	To           time.Time `json:"to"`
+	Limit        int       `json:"limit"`
+	Fields       []string  `json:"fields"`
+	IsHardDelete bool      `json:"is_hard_delete"`
+}
+
+// envelope is the response shape used by the GDPR service
+type envelope struct {
+	StatusCode int         `json:"statusCode"`
+	Message    string      `json:"message,omitempty"`
+	Data       interface{} `json:"data,omitempty"`
+}
+
+// page is the data returned by list actions
+type page struct {
+	Results      []*record `json:"results"`
+	LastRangeKey string    `json:"lastRangeKey,omitempty"`
+}
+
+// partitionKeyPage is the data of a fetchPartitionKeys response
+type partitionKeyPage struct {
+	Results      []string `json:"results"`
+	LastRangeKey string   `json:"lastRangeKey,omitempty"`
+}
+
+// NewServer starts a fake GDPR service. Callers must Close it when done.
+func NewServer(options ...Option) *Server {
+	s := &Server{
+		apiKey:     APIKey,
+		authHeader: gdprclient.DefaultAuthHeader,
+		pageSize:   DefaultPageSize,
+		stores: map[string]map[string]*record{
+			"":       {},
+			"delete": {},
+		},
+		versions: make(map[*record]int),
+	}
+
+	for _, option := range options {
+		option(s)
+	}
+
+	s.Server = httptest.NewServer(http.HandlerFunc(s.handle))
+	return s
+}
+
+// Client returns a gdprclient.Client pointed at the server using its API key, auth header and
+// endpoint scheme. Retries are disabled so failures surface immediately in tests. It fails t if an
+// option is invalid.
+func (s *Server) Client(t testing.TB, options ...gdprclient.ClientOption) *gdprclient.Client {
+	t.Helper()
+
+	defaults := []gdprclient.ClientOption{
+		gdprclient.WithMaxRetries(0),
+		gdprclient.WithAuthHeader(s.authHeader),
+	}
+	if s.pathEndpoints {
+		defaults = append(defaults, gdprclient.WithEndpointResolver(gdprclient.PathEndpoints))
+	}
+	client, err := gdprclient.NewClient(s.URL, s.apiKey, append(defaults, options...)...)
+	if err != nil {
+		t.Fatalf("gdprclienttest: failed to create client: %v", err)
+	}
+	return client
+}
+
+// InfoRequests returns a snapshot of every stored info request ordered by partition and range key
+func (s *Server) InfoRequests() []gdprclient.InfoRequest {
+	var requests []gdprclient.InfoRequest
+	for _, r := range s.snapshot("") {
+		requests = append(requests, gdprclient.InfoRequest(*r))
+	}
+	return requests
+}
+
+// DeleteRequests returns a snapshot of every stored delete request ordered by partition and range key
+func (s *Server) DeleteRequests() []gdprclient.DeleteRequest {
+	var requests []gdprclient.DeleteRequest
+	for _, r := range s.snapshot("delete") {
+		requests = append(requests, gdprclient.DeleteRequest(*r))
+	}
+	return requests
+}
+
+// snapshot copies the records of a controller in key order
+func (s *Server) snapshot(controller string) []*record {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	records := s.sorted(s.stores[controller], func(*record) bool { return true })
+	copies := make([]*record, len(records))
+	for i, r := range records {
+		copied := *r
+		copies[i] = &copied
+	}
+	return copies
+}
+
+// handle routes a request to the matching action
+func (s *Server) handle(w http.ResponseWriter, r *http.Request) {
+	if r.Method != http.MethodPost {
+		writeJSON(w, http.StatusMethodNotAllowed, envelope{StatusCode: http.StatusMethodNotAllowed, Message: "method not allowed"})
+		return
+	}
+
+	if r.Header.Get(s.authHeader) != s.apiKey {
+		writeJSON(w, http.StatusUnauthorized, envelope{StatusCode: http.StatusUnauthorized, Message: "invalid api key"})
+		return
+	}
+
+	var body requestBody
+	if err := json.NewDecoder(r.Body).Decode(&body); err != nil {
+		writeJSON(w, http.StatusBadRequest, envelope{StatusCode: http.StatusBadRequest, Message: "invalid body: " + err.Error()})
+		return
+	}
+
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	controller, action := s.route(r)
+	store, ok := s.stores[controller]
+	if !ok {
+		writeJSON(w, http.StatusOK, envelope{StatusCode: http.StatusNotFound, Message: "unknown controller"})
+		return
+	}
+
+	if action == "fetch" {
+		if stored, ok := store[key(body.PartitionKey, body.RangeKey)]; ok {
+			etag := fmt.Sprintf(`"%d"`, s.versions[stored])
+			w.Header().Set("ETag", etag)
+			if r.Header.Get("If-None-Match") == etag {
+				w.WriteHeader(http.StatusNotModified)
+				return
+			}
+		}
+	}
+
+	writeJSON(w, http.StatusOK, s.dispatch(store, action, r.Header.Get("If-Match"), body))
+}
+
+// route returns the controller and action a request targets, from its query or, with
+// WithPathEndpoints, from its path under gdprclient.DefaultBasePath
+func (s *Server) route(r *http.Request) (string, string) {
+	if !s.pathEndpoints {
+		return r.URL.Query().Get("controller"), r.URL.Query().Get("action")
+	}
+
+	segments := strings.Split(strings.TrimPrefix(r.URL.Path, gdprclient.DefaultBasePath+"/"), "/")
+	if len(segments) == 1 {
+		return "", segments[0]
+	}
+	return strings.Join(segments[:len(segments)-1], "/"), segments[len(segments)-1]
+}
+
+// touch gives r a new version after a write
+func (s *Server) touch(r *record) {
+	s.nextVersion++
+	s.versions[r] = s.nextVersion
+}
+
+// dispatch runs an action against a store and returns the response envelope. A non-empty
+// ifMatch makes an update fail with 409 unless it equals the record's Modified timestamp.
+func (s *Server) dispatch(store map[string]*record, action, ifMatch string, body requestBody) envelope {
+	now := time.Now().UTC().Format(time.RFC3339)
+
+	switch action {
+	case "create":
+		s.nextID++
+		r := &record{
+			PartitionKey: body.PartitionKey,
+			RangeKey:     fmt.Sprintf("%08d", s.nextID),
+			Type:         body.Type,
+			Status:       gdprclient.StatusPending,
+			Created:      now,
+			Modified:     now,
+			CreatedBy:    body.CreatedBy,
+		}
+		s.touch(r)
+		store[key(r.PartitionKey, r.RangeKey)] = r
+		return envelope{StatusCode: http.StatusOK, Data: r}
+
+	case "fetch":
+		r, ok := store[key(body.PartitionKey, body.RangeKey)]
+		if !ok {
+			return envelope{StatusCode: http.StatusNotFound, Message: "request not found"}
+		}
+		return envelope{StatusCode: http.StatusOK, Data: r}
+
+	case "update":
+		r, ok := store[key(body.PartitionKey, body.RangeKey)]
+		if !ok {
+			return envelope{StatusCode: http.StatusNotFound, Message: "request not found"}
+		}
+		if ifMatch != "" && ifMatch != r.Modified {
+			return envelope{StatusCode: http.StatusConflict, Message: "request was modified"}
+		}
+		if body.Type != "" {
+			r.Type = body.Type
+		}
+		if body.Status != "" {
+			r.Status = body.Status
+		}
+		r.Modified = now
+		s.touch(r)
+		return envelope{StatusCode: http.StatusOK, Data: r}
+
+	case "delete":
+		k := key(body.PartitionKey, body.RangeKey)
+		r, ok := store[k]
+		if !ok {
+			return envelope{StatusCode: http.StatusNotFound, Message: "request not found"}
+		}
+		if body.IsHardDelete {
+			delete(store, k)
+			delete(s.versions, r)
+		} else {
+			r.Status = gdprclient.StatusDeleted
+			r.Modified = now
+			s.touch(r)
+		}
+		return envelope{StatusCode: http.StatusOK}
+
+	case "restore":
+		r, ok := store[key(body.PartitionKey, body.RangeKey)]
+		if !ok {
+			return envelope{StatusCode: http.StatusNotFound, Message: "request not found"}
+		}
+		if r.Status != gdprclient.StatusDeleted {
+			return envelope{StatusCode: http.StatusConflict, Message: "request is not deleted"}
+		}
+		r.Status = gdprclient.StatusPending
+		r.Modified = now
+		s.touch(r)
+		return envelope{StatusCode: http.StatusOK}
+
+	case "fetchAll":
+		return s.page(store, body, func(r *record) bool { return r.PartitionKey == body.PartitionKey })
+
+	case "fetchByType":
+		return s.page(store, body, func(r *record) bool { return r.Type == body.Type })
+
+	case "fetchByStatus":
+		return s.page(store, body, func(r *record) bool { return r.Status == body.Status })
+
+	case "fetchByCreator":
+		return s.page(store, body, func(r *record) bool { return r.CreatedBy == body.CreatedBy })
+
+	case "fetchPartitionKeys":
+		return s.partitionKeys(store, body)
+
+	case "fetchByDateRange":
+		return s.page(store, body, func(r *record) bool {
+			created, err := time.Parse(time.RFC3339, r.Created)
+			return err == nil && !created.Before(body.From) && created.Before(body.To)
+		})
+	}
+
+	return envelope{StatusCode: http.StatusNotFound, Message: "unknown action " + action}
+}
+
+// page returns the matching records after body.LastRangeKey, limited to the requested or default
+// page size and projected to body.Fields when set
+func (s *Server) page(store map[string]*record, body requestBody, match func(*record) bool) envelope {
+	records := s.sorted(store, match)
+	limit := body.Limit
+	if limit <= 0 {
+		limit = s.pageSize
+	}
+
+	start := 0
+	if body.LastRangeKey != "" {
+		start = sort.Search(len(records), func(i int) bool { return records[i].RangeKey > body.LastRangeKey })
+	}
+
+	result := page{Results: []*record{}}
+	for i := start; i < len(records) && len(result.Results) < limit; i++ {
+		result.Results = append(result.Results, project(records[i], body.Fields))
+	}
+	if start+len(result.Results) < len(records) {
+		result.LastRangeKey = result.Results[len(result.Results)-1].RangeKey
+	}
+
+	return envelope{StatusCode: http.StatusOK, Data: result}
+}
+
+// partitionKeys returns the distinct partition keys after body.LastRangeKey, in order, limited to
+// the requested or default page size
+func (s *Server) partitionKeys(store map[string]*record, body requestBody) envelope {
+	seen := make(map[string]bool)
+	var keys []string
+	for _, r := range store {
+		if !seen[r.PartitionKey] && r.PartitionKey > body.LastRangeKey {
+			seen[r.PartitionKey] = true
+			keys = append(keys, r.PartitionKey)
+		}
+	}
+	sort.Strings(keys)
+
+	limit := body.Limit
+	if limit <= 0 {
+		limit = s.pageSize
+	}
+
+	result := partitionKeyPage{Results: []string{}}
+	for i := 0; i < len(keys) && i < limit; i++ {
+		result.Results = append(result.Results, keys[i])
+	}
+	if len(result.Results) < len(keys) {
+		result.LastRangeKey = result.Results[len(result.Results)-1]
+	}
+
+	return envelope{StatusCode: http.StatusOK, Data: result}
+}
+
+// sorted returns the matching records ordered by range key, then partition key
+func (s *Server) sorted(store map[string]*record, match func(*record) bool) []*record {
+	var records []*record
+	for _, r := range store {
+		if match(r) {
+			records = append(records, r)
+		}
+	}
+	sort.Slice(records, func(i, j int) bool {
+		if records[i].RangeKey != records[j].RangeKey {
+			return records[i].RangeKey < records[j].RangeKey
+		}
+		return records[i].PartitionKey < records[j].PartitionKey
+	})
+	return records
+}
+
+// project copies the key fields of r and those named in fields; no fields means the whole record
+func project(r *record, fields []string) *record {
+	if len(fields) == 0 {
+		return r
+	}
+
+	projected := &record{PartitionKey: r.PartitionKey, RangeKey: r.RangeKey}
+	for _, field := range fields {
+		switch field {
+		case gdprclient.FieldType:
+			projected.Type = r.Type
+		case gdprclient.FieldStatus:
+			projected.Status = r.Status
+		case gdprclient.FieldCreated:
+			projected.Created = r.Created
+		case gdprclient.FieldModified:
+			projected.Modified = r.Modified
+		case gdprclient.FieldCreatedBy:
+			projected.CreatedBy = r.CreatedBy
+		}
+	}
+	return projected
+}
+
+// key identifies a record within a store
+func key(partitionKey, rangeKey string) string {
+	return partitionKey + "|" + rangeKey
+}
+
+// writeJSON writes v as the JSON response body
+func writeJSON(w http.ResponseWriter, statusCode int, v interface{}) {
+	w.Header().Set("Content-Type", "application/json")
+	w.WriteHeader(statusCode)
+	json.NewEncoder(w).Encode(v)
+}
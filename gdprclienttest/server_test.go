@@ -0,0 +1,65 @@
+package gdprclienttest_test
+
+import (
+	"context"
+	"errors"
+	"testing"
+
+	"github.com/cincinnatiai/gdprclient"
+	"github.com/cincinnatiai/gdprclient/gdprclienttest"
+)
+
+func TestServerRoutesAsConfigured(t *testing.T) {
+	tests := []struct {
+		name    string
+		options []gdprclienttest.Option
+	}{
+		{"query endpoints", nil},
+		{"auth header", []gdprclienttest.Option{gdprclienttest.WithAuthHeader("Authorization")}},
+		{"path endpoints", []gdprclienttest.Option{gdprclienttest.WithPathEndpoints()}},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			server := gdprclienttest.NewServer(tt.options...)
+			defer server.Close()
+
+			ctx := context.Background()
+			client := server.Client(t)
+
+			info, err := client.CreateInfoRequest(ctx, gdprclient.CreateInfoRequestInput{PartitionKey: "user-1", Type: gdprclient.TypeInfoRequest, CreatedBy: "test"})
+			if err != nil {
+				t.Fatalf("CreateInfoRequest: %v", err)
+			}
+			deleteRequest, err := client.CreateDeleteRequest(ctx, gdprclient.CreateDeleteRequestInput{PartitionKey: "user-1", Type: gdprclient.TypeDeleteRequest, CreatedBy: "test"})
+			if err != nil {
+				t.Fatalf("CreateDeleteRequest: %v", err)
+			}
+
+			if _, err := client.FetchInfoRequest(ctx, gdprclient.FetchRequestInput{PartitionKey: "user-1", RangeKey: info.RangeKey}); err != nil {
+				t.Errorf("FetchInfoRequest: %v", err)
+			}
+			if _, err := client.FetchDeleteRequest(ctx, gdprclient.FetchRequestInput{PartitionKey: "user-1", RangeKey: deleteRequest.RangeKey}); err != nil {
+				t.Errorf("FetchDeleteRequest: %v", err)
+			}
+			if n := len(server.InfoRequests()); n != 1 {
+				t.Errorf("server holds %d info requests, want 1", n)
+			}
+			if n := len(server.DeleteRequests()); n != 1 {
+				t.Errorf("server holds %d delete requests, want 1", n)
+			}
+		})
+	}
+}
+
+func TestServerRequiresConfiguredAuthHeader(t *testing.T) {
+	server := gdprclienttest.NewServer(gdprclienttest.WithAuthHeader("Authorization"))
+	defer server.Close()
+
+	// The key in the default header is not accepted
+	client := server.Client(t, gdprclient.WithAuthHeader(gdprclient.DefaultAuthHeader))
+	_, err := client.CreateInfoRequest(context.Background(), gdprclient.CreateInfoRequestInput{PartitionKey: "user-1", Type: gdprclient.TypeInfoRequest, CreatedBy: "test"})
+	if !errors.Is(err, gdprclient.ErrUnauthorized) {
+		t.Errorf("err = %v, want ErrUnauthorized", err)
+	}
+}
@@ -0,0 +1,3 @@
+module github.com/cincinnatiai/gdprclient
+
+go 1.16
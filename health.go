@@ -0,0 +1,26 @@
+package gdprclient
+
+import (
+	"context"
+	"fmt"
+)
+
+// healthCheckKey is the partition and range key HealthCheck fetches; no real request uses it
+const healthCheckKey = "__gdprclient_health_check__"
+
+// HealthCheck confirms the service is reachable and accepts the client's credentials by fetching
+// a request that does not exist. It returns nil when the service answers, an error matching
+// ErrUnauthorized when the credentials are rejected, and the underlying error otherwise.
+func (c *Client) HealthCheck(ctx context.Context) error {
+	input := FetchRequestInput{
+		PartitionKey: healthCheckKey,
+		RangeKey:     healthCheckKey,
+	}
+
+	err := c.do(ctx, "", "fetch", "", input, nil)
+	if err == nil || IsNotFound(err) {
+		return nil
+	}
+
+	return fmt.Errorf("health check failed: %w", err)
+}
@@ -0,0 +1,106 @@
+package gdprclient
+
+import (
+	"encoding/json"
+	"testing"
+	"time"
+)
+
+func TestInputWireFormat(t *testing.T) {
+	from := time.Date(2025, 3, 1, 0, 0, 0, 0, time.UTC)
+	to := time.Date(2025, 4, 1, 0, 0, 0, 0, time.UTC)
+
+	tests := []struct {
+		name  string
+		input interface{}
+		want  string
+	}{
+		{
+			"CreateInfoRequestInput",
+			CreateInfoRequestInput{PartitionKey: "p", Type: TypeInfoRequest, CreatedBy: "svc", ApiKey: "secret"},
+			`{"partition_key":"p","type":"INFO_REQUEST","created_by":"svc"}`,
+		},
+		{
+			"CreateDeleteRequestInput",
+			CreateDeleteRequestInput{PartitionKey: "p", Type: TypeDeleteRequest, CreatedBy: "svc", ApiKey: "secret"},
+			`{"partition_key":"p","type":"DELETE_REQUEST","created_by":"svc"}`,
+		},
+		{
+			"FetchRequestInput",
+			FetchRequestInput{PartitionKey: "p", RangeKey: "r", ApiKey: "secret", ifNoneMatch: `"etag"`},
+			`{"partition_key":"p","range_key":"r"}`,
+		},
+		{
+			"UpdateRequestInput",
+			UpdateRequestInput{PartitionKey: "p", RangeKey: "r", Type: TypeInfoRequest, Status: StatusComplete, IfMatch: "m", ApiKey: "secret"},
+			`{"partition_key":"p","range_key":"r","type":"INFO_REQUEST","status":"COMPLETE"}`,
+		},
+		{
+			"UpdateRequestInput empty",
+			UpdateRequestInput{PartitionKey: "p", RangeKey: "r"},
+			`{"partition_key":"p","range_key":"r"}`,
+		},
+		{
+			"FetchAllRequestInput",
+			FetchAllRequestInput{PartitionKey: "p", LastRangeKey: "r", Fields: []string{FieldStatus}, SortBy: FieldCreated, SortOrder: SortDescending, Limit: 10, ApiKey: "secret"},
+			`{"partition_key":"p","last_range_key":"r","fields":["status"],"sort_by":"created","sort_order":"desc","limit":10}`,
+		},
+		{
+			"FetchAllRequestInput empty",
+			FetchAllRequestInput{PartitionKey: "p"},
+			`{"partition_key":"p"}`,
+		},
+		{
+			"FetchByTypeInput",
+			FetchByTypeInput{Type: TypeDeleteRequest, LastRangeKey: "r", Fields: []string{FieldType}, SortBy: FieldModified, SortOrder: SortAscending, Limit: 5, ApiKey: "secret"},
+			`{"type":"DELETE_REQUEST","last_range_key":"r","fields":["type"],"sort_by":"modified","sort_order":"asc","limit":5}`,
+		},
+		{
+			"FetchByStatusInput",
+			FetchByStatusInput{Status: StatusPending, LastRangeKey: "r", Limit: 5, ApiKey: "secret"},
+			`{"status":"PENDING","last_range_key":"r","limit":5}`,
+		},
+		{
+			"FetchByCreatorInput",
+			FetchByCreatorInput{CreatedBy: "svc", LastRangeKey: "r", Limit: 5, ApiKey: "secret"},
+			`{"created_by":"svc","last_range_key":"r","limit":5}`,
+		},
+		{
+			"FetchByDateRangeInput",
+			FetchByDateRangeInput{From: from, To: to, LastRangeKey: "r", Limit: 5, ApiKey: "secret"},
+			`{"from":"2025-03-01T00:00:00Z","to":"2025-04-01T00:00:00Z","last_range_key":"r","limit":5}`,
+		},
+		{
+			"DeleteRequestInput",
+			DeleteRequestInput{PartitionKey: "p", RangeKey: "r", IsHardDelete: true, ApiKey: "secret"},
+			`{"partition_key":"p","range_key":"r","is_hard_delete":true}`,
+		},
+		{
+			"DeleteRequestInput soft",
+			DeleteRequestInput{PartitionKey: "p", RangeKey: "r"},
+			`{"partition_key":"p","range_key":"r","is_hard_delete":false}`,
+		},
+		{
+			"restoreRequestInput",
+			restoreRequestInput{PartitionKey: "p", RangeKey: "r"},
+			`{"partition_key":"p","range_key":"r"}`,
+		},
+		{
+			"partitionKeysInput",
+			partitionKeysInput{LastRangeKey: "p", Limit: 5},
+			`{"last_range_key":"p","limit":5}`,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got, err := json.Marshal(tt.input)
+			if err != nil {
+				t.Fatalf("json.Marshal: %v", err)
+			}
+			if string(got) != tt.want {
+				t.Errorf("wire format\n got: %s\nwant: %s", got, tt.want)
+			}
+		})
+	}
+}
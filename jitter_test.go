@@ -0,0 +1,138 @@
+package gdprclient
+
+import (
+	"context"
+	"math/rand"
+	"net/http"
+	"reflect"
+	"testing"
+	"time"
+)
+
+func TestJitterStrategyBounds(t *testing.T) {
+	const samples = 1000
+
+	tests := []struct {
+		name     string
+		strategy JitterStrategy
+		bounds   func(backoff, maxBackoff time.Duration) (low, high time.Duration)
+	}{
+		{"proportional", JitterProportional, func(b, limit time.Duration) (time.Duration, time.Duration) {
+			// Lengthened up to the cap below it, shortened at it
+			if b < limit {
+				high := time.Duration(float64(b) * 1.25)
+				if high > limit {
+					high = limit
+				}
+				return b, high
+			}
+			return time.Duration(float64(b) * 0.75), b
+		}},
+		{"none", JitterNone, func(b, limit time.Duration) (time.Duration, time.Duration) { return b, b }},
+		{"full", JitterFull, func(b, limit time.Duration) (time.Duration, time.Duration) { return 0, b }},
+		{"equal", JitterEqual, func(b, limit time.Duration) (time.Duration, time.Duration) { return b / 2, b }},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			policy := RetryPolicy{
+				MaxRetries:     6,
+				InitialBackoff: 100 * time.Millisecond,
+				MaxBackoff:     2 * time.Second,
+				BackoffFactor:  2,
+				Jitter:         0.25,
+				JitterStrategy: tt.strategy,
+			}
+			client := newStubClient(t, nil, WithRetryPolicy(policy), WithRandSource(rand.NewSource(1)))
+
+			for attempt := 0; attempt < policy.MaxRetries; attempt++ {
+				// The exponential backoff for this attempt, capped at MaxBackoff
+				backoff := policy.InitialBackoff << uint(attempt)
+				if backoff > policy.MaxBackoff {
+					backoff = policy.MaxBackoff
+				}
+				low, high := tt.bounds(backoff, policy.MaxBackoff)
+
+				for i := 0; i < samples; i++ {
+					if got := client.calculateBackoff(attempt, 0); got < low || got > high {
+						t.Fatalf("attempt %d: backoff %v outside [%v, %v]", attempt, got, low, high)
+					}
+				}
+			}
+		})
+	}
+}
+
+func TestDecorrelatedJitterRecurrence(t *testing.T) {
+	policy := RetryPolicy{
+		MaxRetries:     10,
+		InitialBackoff: 50 * time.Millisecond,
+		MaxBackoff:     3 * time.Second,
+		JitterStrategy: JitterDecorrelated,
+	}
+	client := newStubClient(t, nil, WithRetryPolicy(policy), WithRandSource(rand.NewSource(1)))
+
+	var previous, longest time.Duration
+	for i := 0; i < 10000; i++ {
+		// Each wait is random(base, previous*3), with previous no lower than base, capped at MaxBackoff
+		high := 3 * previous
+		if high < 3*policy.InitialBackoff {
+			high = 3 * policy.InitialBackoff
+		}
+		if high > policy.MaxBackoff {
+			high = policy.MaxBackoff
+		}
+
+		backoff := client.calculateBackoff(i%policy.MaxRetries, previous)
+		if backoff < policy.InitialBackoff || backoff > high {
+			t.Fatalf("iteration %d: backoff %v after %v outside [%v, %v]", i, backoff, previous, policy.InitialBackoff, high)
+		}
+		previous = backoff
+		if backoff > longest {
+			longest = backoff
+		}
+	}
+
+	if longest != policy.MaxBackoff {
+		t.Errorf("longest backoff = %v, want the recurrence to reach MaxBackoff %v", longest, policy.MaxBackoff)
+	}
+}
+
+func TestSeededBackoffs(t *testing.T) {
+	// Exponential backoffs of 1ms, 2ms, 4ms and 8ms, the last at MaxBackoff, randomized by a source
+	// seeded with 1
+	tests := []struct {
+		name     string
+		strategy JitterStrategy
+		want     []time.Duration
+	}{
+		{"proportional", JitterProportional, []time.Duration{1302330, 2940509, 5329120, 6249143}},
+		{"full", JitterFull, []time.Duration{604660, 1881018, 2658240, 3501713}},
+		{"equal", JitterEqual, []time.Duration{802330, 1940509, 3329120, 5750856}},
+		{"decorrelated", JitterDecorrelated, []time.Duration{2209320, 6293147, 8 * time.Millisecond, 8 * time.Millisecond}},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			transport := roundTripFunc(func(req *http.Request) (*http.Response, error) {
+				return stubResponse(req, http.StatusServiceUnavailable, ""), nil
+			})
+			policy := RetryPolicy{
+				MaxRetries:     4,
+				InitialBackoff: time.Millisecond,
+				MaxBackoff:     8 * time.Millisecond,
+				BackoffFactor:  2,
+				Jitter:         0.5,
+				JitterStrategy: tt.strategy,
+			}
+			client := newStubClient(t, transport, WithRetryPolicy(policy), WithRandSource(rand.NewSource(1)))
+
+			var md ResponseMetadata
+			client.FetchInfoRequest(CaptureResponseMetadata(context.Background(), &md), FetchRequestInput{PartitionKey: "p", RangeKey: "r"})
+
+			if !reflect.DeepEqual(md.Backoffs, tt.want) {
+				t.Errorf("Backoffs = %v, want %v", md.Backoffs, tt.want)
+			}
+		})
+	}
+}
@@ -0,0 +1,25 @@
+package gdprclient
+
+// Logger receives diagnostic messages from the client. Request and response
+// bodies are only passed to the logger when WithBodyLogging is used.
+type Logger interface {
+	Debugf(format string, v ...interface{})
+	Infof(format string, v ...interface{})
+}
+
+// noopLogger discards all messages and is the default Logger
+type noopLogger struct{}
+
+func (noopLogger) Debugf(format string, v ...interface{}) {}
+
+func (noopLogger) Infof(format string, v ...interface{}) {}
+
+// WithLogger sets the logger used for diagnostic messages. Passing nil silences logging.
+func WithLogger(logger Logger) ClientOption {
+	return func(c *Client) {
+		if logger == nil {
+			logger = noopLogger{}
+		}
+		c.logger = logger
+	}
+}
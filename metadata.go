@@ -0,0 +1,51 @@
+package gdprclient
+
+import (
+	"context"
+	"net/http"
+	"time"
+)
+
+// RequestIDHeader is the header the gateway uses to identify a request in its logs
+const RequestIDHeader = "X-Request-Id"
+
+// ResponseMetadata describes the HTTP exchange behind a call. It is filled in by calls made with a
+// context from CaptureResponseMetadata, whether the call succeeds or fails; the response fields stay
+// empty when no response was received. Results served from the cache leave it untouched.
+type ResponseMetadata struct {
+	StatusCode int         // HTTP status code of the final attempt
+	Header     http.Header // Response headers of the final attempt
+	RequestID  string      // Value of RequestIDHeader, if the gateway sent one
+	Attempts   int         // Attempts sent, so a value above 1 means the call needed retries
+
+	// Backoffs are the waits before each retry, in order, including Retry-After. A wait cut short
+	// by the context holds the time actually waited.
+	Backoffs []time.Duration
+}
+
+// responseMetadataKey is the context key for the caller's ResponseMetadata
+type responseMetadataKey struct{}
+
+// CaptureResponseMetadata returns a context that makes calls record their response metadata in md.
+// Use a separate ResponseMetadata for each call; concurrent calls sharing one overwrite each other.
+func CaptureResponseMetadata(ctx context.Context, md *ResponseMetadata) context.Context {
+	return context.WithValue(ctx, responseMetadataKey{}, md)
+}
+
+// recordResponseMetadata copies the attempt count, backoffs, response status and headers into the
+// context's ResponseMetadata, if any
+func recordResponseMetadata(ctx context.Context, resp *http.Response, attempts int, backoffs []time.Duration) {
+	md, ok := ctx.Value(responseMetadataKey{}).(*ResponseMetadata)
+	if !ok || md == nil {
+		return
+	}
+
+	md.Attempts = attempts
+	md.Backoffs = backoffs
+	if resp == nil {
+		return
+	}
+	md.StatusCode = resp.StatusCode
+	md.Header = resp.Header.Clone()
+	md.RequestID = resp.Header.Get(RequestIDHeader)
+}
@@ -0,0 +1,48 @@
+package gdprclient
+
+import "time"
+
+// Metrics receives one observation per client call, after all retries have finished.
+// It can be backed by a Prometheus collector or any other metrics system.
+type Metrics interface {
+	// ObserveRequest is called with the operation (e.g. "create" or "delete/fetch"), the final
+	// HTTP status code (0 if no response was received), the total duration including backoff,
+	// and the number of attempts sent.
+	ObserveRequest(operation string, statusCode int, duration time.Duration, attempts int)
+}
+
+// BackoffObserver can be implemented by a Metrics to also receive the waits between the attempts
+// of a call. ObserveBackoffs is called after ObserveRequest, only for calls that retried, with the
+// wait before each retry in order.
+type BackoffObserver interface {
+	ObserveBackoffs(operation string, backoffs []time.Duration)
+}
+
+// APIKeyObserver can be implemented by a Metrics to learn when a call succeeded only with one of
+// the WithFallbackAPIKeys. index is the key's position in that list, starting at 1.
+type APIKeyObserver interface {
+	ObserveAPIKeyFallback(operation string, index int)
+}
+
+// WithMetrics sets the metrics hook invoked for every call
+func WithMetrics(metrics Metrics) ClientOption {
+	return func(c *Client) {
+		c.metrics = metrics
+	}
+}
+
+// observeAPIKeyFallback reports a call that succeeded with a fallback API key
+func (c *Client) observeAPIKeyFallback(operation string, index int) {
+	c.logger.Infof("gdprclient: %s succeeded with fallback API key %d", operation, index)
+	if observer, ok := c.metrics.(APIKeyObserver); ok {
+		observer.ObserveAPIKeyFallback(operation, index)
+	}
+}
+
+// operationName labels a controller action, e.g. "fetch" or "delete/fetch"
+func operationName(controller, action string) string {
+	if controller == "" {
+		return action
+	}
+	return controller + "/" + action
+}
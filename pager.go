@@ -0,0 +1,252 @@
+package gdprclient
+
+import "context"
+
+// infoPageFetcher fetches the page of info requests that starts after lastRangeKey
+type infoPageFetcher func(ctx context.Context, lastRangeKey string) (*PaginatedInfoResponse, error)
+
+// deletePageFetcher fetches the page of delete requests that starts after lastRangeKey
+type deletePageFetcher func(ctx context.Context, lastRangeKey string) (*PaginatedDeleteResponse, error)
+
+// InfoRequestPager iterates over the pages of an info request listing by following LastRangeKey
+// until a page reports no next page
+type InfoRequestPager struct {
+	fetch        infoPageFetcher
+	action       string
+	lastRangeKey string
+	done         bool
+}
+
+// HasMore reports whether another call to Next may return results
+func (p *InfoRequestPager) HasMore() bool {
+	return !p.done
+}
+
+// Cursor returns a token that Resume accepts to continue after the last page returned by Next,
+// e.g. from a checkpoint after a restart. It is empty once the listing is done.
+func (p *InfoRequestPager) Cursor() string {
+	if p.done {
+		return ""
+	}
+	return encodeCursor("", p.action, p.lastRangeKey)
+}
+
+// Resume continues the listing from a token returned by Cursor. It fails, without changing the
+// pager, if the token is malformed or was taken from a different listing action.
+func (p *InfoRequestPager) Resume(token string) error {
+	lastRangeKey, err := decodeCursor(token, "", p.action)
+	if err != nil {
+		return err
+	}
+	p.lastRangeKey = lastRangeKey
+	p.done = false
+	return nil
+}
+
+// Next fetches the next page of info requests. After the last page HasMore returns false
+// and Next returns no results. A failed page can be retried by calling Next again.
+func (p *InfoRequestPager) Next(ctx context.Context) ([]InfoRequest, error) {
+	if p.done {
+		return nil, nil
+	}
+
+	page, err := p.fetch(ctx, p.lastRangeKey)
+	if err != nil {
+		return nil, err
+	}
+
+	p.lastRangeKey = page.LastRangeKey
+	p.done = !page.HasNextPage()
+
+	return page.Results, nil
+}
+
+// DeleteRequestPager iterates over the pages of a delete request listing by following LastRangeKey
+// until a page reports no next page
+type DeleteRequestPager struct {
+	fetch        deletePageFetcher
+	action       string
+	lastRangeKey string
+	done         bool
+}
+
+// HasMore reports whether another call to Next may return results
+func (p *DeleteRequestPager) HasMore() bool {
+	return !p.done
+}
+
+// Cursor returns a token that Resume accepts to continue after the last page returned by Next,
+// e.g. from a checkpoint after a restart. It is empty once the listing is done.
+func (p *DeleteRequestPager) Cursor() string {
+	if p.done {
+		return ""
+	}
+	return encodeCursor("delete", p.action, p.lastRangeKey)
+}
+
+// Resume continues the listing from a token returned by Cursor. It fails, without changing the
+// pager, if the token is malformed or was taken from a different listing action.
+func (p *DeleteRequestPager) Resume(token string) error {
+	lastRangeKey, err := decodeCursor(token, "delete", p.action)
+	if err != nil {
+		return err
+	}
+	p.lastRangeKey = lastRangeKey
+	p.done = false
+	return nil
+}
+
+// Next fetches the next page of delete requests. After the last page HasMore returns false
+// and Next returns no results. A failed page can be retried by calling Next again.
+func (p *DeleteRequestPager) Next(ctx context.Context) ([]DeleteRequest, error) {
+	if p.done {
+		return nil, nil
+	}
+
+	page, err := p.fetch(ctx, p.lastRangeKey)
+	if err != nil {
+		return nil, err
+	}
+
+	p.lastRangeKey = page.LastRangeKey
+	p.done = !page.HasNextPage()
+
+	return page.Results, nil
+}
+
+// FetchAllInfoRequestsPager returns a pager over FetchAllInfoRequests starting at input.LastRangeKey
+func (c *Client) FetchAllInfoRequestsPager(input FetchAllRequestInput) *InfoRequestPager {
+	return &InfoRequestPager{
+		action:       "fetchAll",
+		lastRangeKey: input.LastRangeKey,
+		fetch: func(ctx context.Context, lastRangeKey string) (*PaginatedInfoResponse, error) {
+			input.LastRangeKey = lastRangeKey
+			return c.FetchAllInfoRequests(ctx, input)
+		},
+	}
+}
+
+// FetchInfoRequestsByTypePager returns a pager over FetchInfoRequestsByType starting at input.LastRangeKey
+func (c *Client) FetchInfoRequestsByTypePager(input FetchByTypeInput) *InfoRequestPager {
+	return &InfoRequestPager{
+		action:       "fetchByType",
+		lastRangeKey: input.LastRangeKey,
+		fetch: func(ctx context.Context, lastRangeKey string) (*PaginatedInfoResponse, error) {
+			input.LastRangeKey = lastRangeKey
+			return c.FetchInfoRequestsByType(ctx, input)
+		},
+	}
+}
+
+// FetchInfoRequestsByStatusPager returns a pager over FetchInfoRequestsByStatus starting at input.LastRangeKey
+func (c *Client) FetchInfoRequestsByStatusPager(input FetchByStatusInput) *InfoRequestPager {
+	return &InfoRequestPager{
+		action:       "fetchByStatus",
+		lastRangeKey: input.LastRangeKey,
+		fetch: func(ctx context.Context, lastRangeKey string) (*PaginatedInfoResponse, error) {
+			input.LastRangeKey = lastRangeKey
+			return c.FetchInfoRequestsByStatus(ctx, input)
+		},
+	}
+}
+
+// FetchInfoRequestsByCreatorPager returns a pager over FetchInfoRequestsByCreator starting at input.LastRangeKey
+func (c *Client) FetchInfoRequestsByCreatorPager(input FetchByCreatorInput) *InfoRequestPager {
+	return &InfoRequestPager{
+		action:       "fetchByCreator",
+		lastRangeKey: input.LastRangeKey,
+		fetch: func(ctx context.Context, lastRangeKey string) (*PaginatedInfoResponse, error) {
+			input.LastRangeKey = lastRangeKey
+			return c.FetchInfoRequestsByCreator(ctx, input)
+		},
+	}
+}
+
+// FetchRequestsByCreatorPager returns a pager over FetchInfoRequestsByCreator starting at input.LastRangeKey.
+//
+// Deprecated: use FetchInfoRequestsByCreatorPager.
+func (c *Client) FetchRequestsByCreatorPager(input FetchByCreatorInput) *InfoRequestPager {
+	return c.FetchInfoRequestsByCreatorPager(input)
+}
+
+// FetchInfoRequestsByDateRangePager returns a pager over FetchInfoRequestsByDateRange starting at input.LastRangeKey
+func (c *Client) FetchInfoRequestsByDateRangePager(input FetchByDateRangeInput) *InfoRequestPager {
+	return &InfoRequestPager{
+		action:       "fetchByDateRange",
+		lastRangeKey: input.LastRangeKey,
+		fetch: func(ctx context.Context, lastRangeKey string) (*PaginatedInfoResponse, error) {
+			input.LastRangeKey = lastRangeKey
+			return c.FetchInfoRequestsByDateRange(ctx, input)
+		},
+	}
+}
+
+// FetchAllDeleteRequestsPager returns a pager over FetchAllDeleteRequests starting at input.LastRangeKey
+func (c *Client) FetchAllDeleteRequestsPager(input FetchAllRequestInput) *DeleteRequestPager {
+	return &DeleteRequestPager{
+		action:       "fetchAll",
+		lastRangeKey: input.LastRangeKey,
+		fetch: func(ctx context.Context, lastRangeKey string) (*PaginatedDeleteResponse, error) {
+			input.LastRangeKey = lastRangeKey
+			return c.FetchAllDeleteRequests(ctx, input)
+		},
+	}
+}
+
+// FetchDeleteRequestsByStatusPager returns a pager over FetchDeleteRequestsByStatus starting at input.LastRangeKey
+func (c *Client) FetchDeleteRequestsByStatusPager(input FetchByStatusInput) *DeleteRequestPager {
+	return &DeleteRequestPager{
+		action:       "fetchByStatus",
+		lastRangeKey: input.LastRangeKey,
+		fetch: func(ctx context.Context, lastRangeKey string) (*PaginatedDeleteResponse, error) {
+			input.LastRangeKey = lastRangeKey
+			return c.FetchDeleteRequestsByStatus(ctx, input)
+		},
+	}
+}
+
+// FetchDeleteRequestsByCreatorPager returns a pager over FetchDeleteRequestsByCreator starting at input.LastRangeKey
+func (c *Client) FetchDeleteRequestsByCreatorPager(input FetchByCreatorInput) *DeleteRequestPager {
+	return &DeleteRequestPager{
+		action:       "fetchByCreator",
+		lastRangeKey: input.LastRangeKey,
+		fetch: func(ctx context.Context, lastRangeKey string) (*PaginatedDeleteResponse, error) {
+			input.LastRangeKey = lastRangeKey
+			return c.FetchDeleteRequestsByCreator(ctx, input)
+		},
+	}
+}
+
+// FetchDeleteRequestsByDateRangePager returns a pager over FetchDeleteRequestsByDateRange starting at input.LastRangeKey
+func (c *Client) FetchDeleteRequestsByDateRangePager(input FetchByDateRangeInput) *DeleteRequestPager {
+	return &DeleteRequestPager{
+		action:       "fetchByDateRange",
+		lastRangeKey: input.LastRangeKey,
+		fetch: func(ctx context.Context, lastRangeKey string) (*PaginatedDeleteResponse, error) {
+			input.LastRangeKey = lastRangeKey
+			return c.FetchDeleteRequestsByDateRange(ctx, input)
+		},
+	}
+}
+
+// CountByStatus returns the number of delete requests with the given status. The service has no
+// count action, so this pages through FetchDeleteRequestsByStatus with the largest page size,
+// projected down to the key fields, and sums the page sizes; it costs one call per page.
+func (c *Client) CountByStatus(ctx context.Context, status string) (int, error) {
+	pager := c.FetchDeleteRequestsByStatusPager(FetchByStatusInput{
+		Status: status,
+		Fields: []string{FieldRangeKey},
+		Limit:  MaxPageSize,
+	})
+
+	count := 0
+	for pager.HasMore() {
+		results, err := pager.Next(ctx)
+		if err != nil {
+			return count, err
+		}
+		count += len(results)
+	}
+
+	return count, nil
+}
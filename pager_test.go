@@ -0,0 +1,82 @@
+package gdprclient
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"net/http"
+	"reflect"
+	"testing"
+)
+
+func TestPagerCursorRoundTrip(t *testing.T) {
+	// Pages of range keys, indexed by the last_range_key that requests them
+	pages := map[string][]string{"": {"1", "2"}, "2": {"3", "4"}, "4": {"5"}}
+	transport := roundTripFunc(func(req *http.Request) (*http.Response, error) {
+		var input FetchAllRequestInput
+		data, _ := io.ReadAll(req.Body)
+		if err := json.Unmarshal(data, &input); err != nil {
+			return nil, err
+		}
+		var page PaginatedDeleteResponse
+		for _, key := range pages[input.LastRangeKey] {
+			page.Results = append(page.Results, DeleteRequest{PartitionKey: "p", RangeKey: key})
+			page.LastRangeKey = key
+		}
+		if _, ok := pages[page.LastRangeKey]; !ok {
+			page.LastRangeKey = ""
+		}
+		body, _ := json.Marshal(page)
+		return stubResponse(req, http.StatusOK, fmt.Sprintf(`{"statusCode":200,"data":%s}`, body)), nil
+	})
+	client := newStubClient(t, transport)
+	ctx := context.Background()
+
+	rangeKeys := func(pager *DeleteRequestPager) []string {
+		t.Helper()
+		results, err := pager.Next(ctx)
+		if err != nil {
+			t.Fatalf("Next: %v", err)
+		}
+		var keys []string
+		for _, r := range results {
+			keys = append(keys, r.RangeKey)
+		}
+		return keys
+	}
+
+	first := client.FetchAllDeleteRequestsPager(FetchAllRequestInput{PartitionKey: "p"})
+	rangeKeys(first)
+	token := first.Cursor()
+
+	// Tokens from another controller or action, or that do not decode, leave the pager alone
+	invalid := []string{
+		client.FetchAllInfoRequestsPager(FetchAllRequestInput{PartitionKey: "p"}).Cursor(),
+		client.FetchDeleteRequestsByStatusPager(FetchByStatusInput{Status: StatusPending}).Cursor(),
+		"not a cursor",
+	}
+	resumed := client.FetchAllDeleteRequestsPager(FetchAllRequestInput{PartitionKey: "p"})
+	for _, bad := range invalid {
+		if err := resumed.Resume(bad); !errors.Is(err, ErrInvalidInput) {
+			t.Errorf("Resume(%q) = %v, want ErrInvalidInput", bad, err)
+		}
+	}
+	if resumed.Cursor() != client.FetchAllDeleteRequestsPager(FetchAllRequestInput{}).Cursor() {
+		t.Fatalf("a failed Resume moved the pager")
+	}
+
+	if err := resumed.Resume(token); err != nil {
+		t.Fatalf("Resume: %v", err)
+	}
+	if got, want := rangeKeys(resumed), []string{"3", "4"}; !reflect.DeepEqual(got, want) {
+		t.Errorf("first resumed page = %q, want %q", got, want)
+	}
+	if got, want := rangeKeys(resumed), []string{"5"}; !reflect.DeepEqual(got, want) {
+		t.Errorf("second resumed page = %q, want %q", got, want)
+	}
+	if resumed.HasMore() || resumed.Cursor() != "" {
+		t.Errorf("finished pager has more: %v, cursor %q", resumed.HasMore(), resumed.Cursor())
+	}
+}
@@ -0,0 +1,64 @@
+package gdprclient
+
+import (
+	"context"
+	"fmt"
+	"sort"
+)
+
+// partitionKeysInput is the body of a fetchPartitionKeys call
+type partitionKeysInput struct {
+	LastRangeKey string `json:"last_range_key,omitempty"`
+	Limit        int    `json:"limit,omitempty"`
+}
+
+// partitionKeysPage is a page of a fetchPartitionKeys listing. LastRangeKey is the last partition
+// key of the page.
+type partitionKeysPage struct {
+	Results      []string `json:"results"`
+	LastRangeKey string   `json:"lastRangeKey,omitempty"`
+	HasMore      *bool    `json:"hasMore,omitempty"`
+}
+
+// ListPartitionKeys returns every partition key with at least one info or delete request, sorted
+// and without duplicates. It pages the fetchPartitionKeys action of both controllers, which the
+// backend must implement natively; the client never falls back to scanning every request. Against
+// a backend without the action it returns an error wrapping ErrNotSupported.
+func (c *Client) ListPartitionKeys(ctx context.Context) ([]string, error) {
+	seen := make(map[string]bool)
+	for _, controller := range []string{"", "delete"} {
+		if err := c.listPartitionKeys(ctx, controller, seen); err != nil {
+			return nil, err
+		}
+	}
+
+	keys := make([]string, 0, len(seen))
+	for key := range seen {
+		keys = append(keys, key)
+	}
+	sort.Strings(keys)
+	return keys, nil
+}
+
+// listPartitionKeys adds the partition keys of one controller to seen
+func (c *Client) listPartitionKeys(ctx context.Context, controller string, seen map[string]bool) error {
+	input := partitionKeysInput{Limit: MaxPageSize}
+	for {
+		var page partitionKeysPage
+		if err := c.do(ctx, controller, "fetchPartitionKeys", "", input, &page); err != nil {
+			if IsNotFound(err) {
+				return fmt.Errorf("%w: %s (%v)", ErrNotSupported, operationName(controller, "fetchPartitionKeys"), err)
+			}
+			return err
+		}
+
+		for _, key := range page.Results {
+			seen[key] = true
+		}
+
+		if !hasNextPage(page.LastRangeKey, page.HasMore) {
+			return nil
+		}
+		input.LastRangeKey = page.LastRangeKey
+	}
+}
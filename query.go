@@ -0,0 +1,137 @@
+package gdprclient
+
+import (
+	"context"
+	"fmt"
+)
+
+// Query selectors, one per list endpoint
+const (
+	queryByPartition = "partition"
+	queryByType      = "type"
+	queryByStatus    = "status"
+	queryByCreator   = "creator"
+)
+
+// Query builds the input for a list call, e.g.
+//
+//	NewQuery().ByStatus(StatusPending).WithPageSize(50).StartingAfter(cursor)
+//
+// and is run with QueryInfoRequests or QueryDeleteRequests, which pick the endpoint from the
+// selector. Calling a second selector replaces the first.
+type Query struct {
+	selector string
+	value    string
+
+	lastRangeKey string
+	fields       []string
+	sortBy       string
+	sortOrder    string
+	limit        int
+}
+
+// NewQuery starts an empty query; one of ForPartition, ByType, ByStatus or ByCreator must be called
+func NewQuery() *Query {
+	return &Query{}
+}
+
+// ForPartition selects every request for a partition key
+func (q *Query) ForPartition(partitionKey string) *Query {
+	q.selector, q.value = queryByPartition, partitionKey
+	return q
+}
+
+// ByType selects requests of a type
+func (q *Query) ByType(requestType string) *Query {
+	q.selector, q.value = queryByType, requestType
+	return q
+}
+
+// ByStatus selects requests with a status
+func (q *Query) ByStatus(status string) *Query {
+	q.selector, q.value = queryByStatus, status
+	return q
+}
+
+// ByCreator selects requests made by a creator
+func (q *Query) ByCreator(createdBy string) *Query {
+	q.selector, q.value = queryByCreator, createdBy
+	return q
+}
+
+// WithPageSize sets the page size, up to MaxPageSize
+func (q *Query) WithPageSize(limit int) *Query {
+	q.limit = limit
+	return q
+}
+
+// StartingAfter continues from the LastRangeKey of a previous page
+func (q *Query) StartingAfter(lastRangeKey string) *Query {
+	q.lastRangeKey = lastRangeKey
+	return q
+}
+
+// WithFields limits the fields returned, using the Field constants
+func (q *Query) WithFields(fields ...string) *Query {
+	q.fields = fields
+	return q
+}
+
+// OrderBy asks the backend to sort by a field using SortAscending or SortDescending
+func (q *Query) OrderBy(field, order string) *Query {
+	q.sortBy, q.sortOrder = field, order
+	return q
+}
+
+// Input returns the input struct for the selected endpoint, e.g. a FetchByStatusInput, for use with Do
+func (q *Query) Input() (interface{}, error) {
+	switch q.selector {
+	case queryByPartition:
+		return FetchAllRequestInput{PartitionKey: q.value, LastRangeKey: q.lastRangeKey, Fields: q.fields, SortBy: q.sortBy, SortOrder: q.sortOrder, Limit: q.limit}, nil
+	case queryByType:
+		return FetchByTypeInput{Type: q.value, LastRangeKey: q.lastRangeKey, Fields: q.fields, SortBy: q.sortBy, SortOrder: q.sortOrder, Limit: q.limit}, nil
+	case queryByStatus:
+		return FetchByStatusInput{Status: q.value, LastRangeKey: q.lastRangeKey, Fields: q.fields, SortBy: q.sortBy, SortOrder: q.sortOrder, Limit: q.limit}, nil
+	case queryByCreator:
+		return FetchByCreatorInput{CreatedBy: q.value, LastRangeKey: q.lastRangeKey, Fields: q.fields, SortBy: q.sortBy, SortOrder: q.sortOrder, Limit: q.limit}, nil
+	}
+	return nil, fmt.Errorf("%w: query needs ForPartition, ByType, ByStatus or ByCreator", ErrInvalidInput)
+}
+
+// QueryInfoRequests fetches one page of info requests matching q
+func (c *Client) QueryInfoRequests(ctx context.Context, q *Query) (*PaginatedInfoResponse, error) {
+	input, err := q.Input()
+	if err != nil {
+		return nil, err
+	}
+
+	switch input := input.(type) {
+	case FetchAllRequestInput:
+		return c.FetchAllInfoRequests(ctx, input)
+	case FetchByTypeInput:
+		return c.FetchInfoRequestsByType(ctx, input)
+	case FetchByStatusInput:
+		return c.FetchInfoRequestsByStatus(ctx, input)
+	default:
+		return c.FetchInfoRequestsByCreator(ctx, input.(FetchByCreatorInput))
+	}
+}
+
+// QueryDeleteRequests fetches one page of delete requests matching q. ByType is not supported,
+// as the delete controller has no fetch by type action.
+func (c *Client) QueryDeleteRequests(ctx context.Context, q *Query) (*PaginatedDeleteResponse, error) {
+	input, err := q.Input()
+	if err != nil {
+		return nil, err
+	}
+
+	switch input := input.(type) {
+	case FetchAllRequestInput:
+		return c.FetchAllDeleteRequests(ctx, input)
+	case FetchByStatusInput:
+		return c.FetchDeleteRequestsByStatus(ctx, input)
+	case FetchByCreatorInput:
+		return c.FetchDeleteRequestsByCreator(ctx, input)
+	}
+	return nil, fmt.Errorf("%w: delete requests cannot be queried by type", ErrInvalidInput)
+}
@@ -0,0 +1,108 @@
+package gdprclient
+
+import (
+	"context"
+	"sync"
+	"time"
+)
+
+// rateLimiter is a token bucket that refills at rate tokens per second up to burst tokens.
+// It starts full, at the time of its first use.
+type rateLimiter struct {
+	mu     sync.Mutex
+	rate   float64
+	burst  float64
+	tokens float64
+	last   time.Time
+}
+
+// WithRateLimiter limits outbound attempts, including retries, to requestsPerSecond with bursts of
+// up to burst attempts. Calls block until the limiter admits them or their context is done.
+// A requestsPerSecond of zero or less disables the limiter.
+func WithRateLimiter(requestsPerSecond float64, burst int) ClientOption {
+	return func(c *Client) {
+		if requestsPerSecond <= 0 {
+			c.limiter = nil
+			return
+		}
+		if burst < 1 {
+			burst = 1
+		}
+		c.limiter = &rateLimiter{
+			rate:   requestsPerSecond,
+			burst:  float64(burst),
+			tokens: float64(burst),
+		}
+	}
+}
+
+// WithRetryBudget caps retries across all calls on the client at maxRetries per window, refilled
+// continuously. Once the budget is spent, failed attempts are returned without retrying until it
+// refills, so an outage cannot turn every call into MaxRetries extra requests. First attempts never
+// consume the budget. A maxRetries or window of zero or less removes the budget.
+func WithRetryBudget(maxRetries int, window time.Duration) ClientOption {
+	return func(c *Client) {
+		if maxRetries <= 0 || window <= 0 {
+			c.retryBudget = nil
+			return
+		}
+		c.retryBudget = &rateLimiter{
+			rate:   float64(maxRetries) / window.Seconds(),
+			burst:  float64(maxRetries),
+			tokens: float64(maxRetries),
+		}
+	}
+}
+
+// refill adds the tokens earned since the last refill; l.mu must be held
+func (l *rateLimiter) refill(now time.Time) {
+	if l.last.IsZero() {
+		l.last = now
+		return
+	}
+	l.tokens += now.Sub(l.last).Seconds() * l.rate
+	if l.tokens > l.burst {
+		l.tokens = l.burst
+	}
+	l.last = now
+}
+
+// allow takes a token if one is available, without blocking
+func (l *rateLimiter) allow(now time.Time) bool {
+	l.mu.Lock()
+	defer l.mu.Unlock()
+
+	l.refill(now)
+	if l.tokens < 1 {
+		return false
+	}
+	l.tokens--
+	return true
+}
+
+// wait takes a token, blocking on clock until one is available or ctx is done
+func (l *rateLimiter) wait(ctx context.Context, clock Clock) error {
+	l.mu.Lock()
+	l.refill(clock.Now())
+
+	// Reserve the token now so waiters are admitted in arrival order
+	l.tokens--
+	delay := time.Duration(-l.tokens / l.rate * float64(time.Second))
+	l.mu.Unlock()
+
+	if delay <= 0 {
+		return nil
+	}
+
+	timer := clock.NewTimer(delay)
+	select {
+	case <-timer.C():
+		return nil
+	case <-ctx.Done():
+		timer.Stop()
+		l.mu.Lock()
+		l.tokens++
+		l.mu.Unlock()
+		return ctx.Err()
+	}
+}
@@ -0,0 +1,46 @@
+package gdprclient
+
+import (
+	"context"
+	"errors"
+	"reflect"
+	"testing"
+	"time"
+)
+
+func TestRateLimiterWait(t *testing.T) {
+	clock := &fakeClock{now: time.Date(2025, 3, 24, 12, 0, 0, 0, time.UTC)}
+	limiter := &rateLimiter{rate: 2, burst: 2, tokens: 2}
+
+	// The burst is admitted at once
+	for i := 0; i < 2; i++ {
+		if err := limiter.wait(context.Background(), clock); err != nil {
+			t.Fatalf("wait %d: %v", i, err)
+		}
+	}
+	if len(clock.timers) != 0 {
+		t.Fatalf("burst started %d timers, want none", len(clock.timers))
+	}
+
+	// A cancelled wait stops its timer and hands back the token it reserved
+	ctx, cancel := context.WithCancel(context.Background())
+	cancel()
+	if err := limiter.wait(ctx, clock); !errors.Is(err, context.Canceled) {
+		t.Fatalf("cancelled wait = %v, want context.Canceled", err)
+	}
+	if !clock.timers[0].stopped {
+		t.Error("timer was not stopped after the wait was cancelled")
+	}
+
+	// So half a second later one token has been earned and the next two waits queue behind it
+	clock.advance(500 * time.Millisecond)
+	clock.autoFire = true
+	for i := 0; i < 3; i++ {
+		if err := limiter.wait(context.Background(), clock); err != nil {
+			t.Fatalf("wait %d after cancel: %v", i, err)
+		}
+	}
+	if want := []time.Duration{500 * time.Millisecond, 500 * time.Millisecond, 500 * time.Millisecond}; !reflect.DeepEqual(clock.waits, want) {
+		t.Errorf("waited %v, want %v", clock.waits, want)
+	}
+}
@@ -0,0 +1,107 @@
+package gdprclient
+
+import (
+	"context"
+	"errors"
+	"fmt"
+)
+
+// ReclassifyRequest changes the type of a request between TypeInfoRequest and TypeDeleteRequest
+// and returns the request as now stored.
+//
+// The two types live under different controllers, so this is a move rather than a field edit:
+// updating Type through UpdateInfoRequest or UpdateDeleteRequest only relabels the record in its
+// current controller. ReclassifyRequest creates a new request of newType with the same partition
+// key, creator and status, then hard deletes the original. The service assigns the new request
+// its own RangeKey and Created time, so callers holding the old RangeKey must switch to the one
+// returned. The original is read past the cache set by WithCache, since a stale status would be
+// copied and the current record destroyed. If the status cannot be copied or the original cannot be
+// deleted, the new request is returned together with the error, and both exist until one is
+// deleted.
+// A request that already has newType is returned unchanged.
+func (c *Client) ReclassifyRequest(ctx context.Context, partitionKey, rangeKey, newType string) (*Request, error) {
+	if err := firstError(
+		required(FieldPartitionKey, partitionKey),
+		required(FieldRangeKey, rangeKey),
+		oneOf(FieldType, newType, validTypes, true),
+	); err != nil {
+		return nil, err
+	}
+
+	original, err := c.findRequest(ContextWithBypassCache(ctx), partitionKey, rangeKey)
+	if err != nil {
+		return nil, err
+	}
+	if original.Type == newType {
+		return original, nil
+	}
+
+	status, createdBy := original.Info.Status, original.Info.CreatedBy
+	if original.Delete != nil {
+		status, createdBy = original.Delete.Status, original.Delete.CreatedBy
+	}
+
+	var moved *Request
+	if newType == TypeDeleteRequest {
+		deleteRequest, err := c.CreateDeleteRequest(ctx, CreateDeleteRequestInput{PartitionKey: partitionKey, Type: newType, CreatedBy: createdBy})
+		if err != nil {
+			return nil, fmt.Errorf("failed to create reclassified request: %w", err)
+		}
+		moved = &Request{Type: newType, Delete: deleteRequest}
+	} else {
+		infoRequest, err := c.CreateInfoRequest(ctx, CreateInfoRequestInput{PartitionKey: partitionKey, Type: newType, CreatedBy: createdBy})
+		if err != nil {
+			return nil, fmt.Errorf("failed to create reclassified request: %w", err)
+		}
+		moved = &Request{Type: newType, Info: infoRequest}
+	}
+
+	if status != "" && status != StatusPending {
+		updated, err := c.setStatus(ctx, newType, partitionKey, moved.rangeKey(), status)
+		if err != nil {
+			return moved, fmt.Errorf("failed to copy status to reclassified request %s: %w", moved.rangeKey(), err)
+		}
+		moved = updated
+	}
+
+	remove := DeleteRequestInput{PartitionKey: partitionKey, RangeKey: rangeKey, IsHardDelete: true}
+	if original.Delete != nil {
+		_, err = c.DeleteRequest(ctx, remove)
+	} else {
+		_, err = c.DeleteInfoRequest(ctx, remove)
+	}
+	if err != nil {
+		return moved, fmt.Errorf("reclassified request as %s but failed to delete the original: %w", moved.rangeKey(), err)
+	}
+
+	return moved, nil
+}
+
+// findRequest fetches a request from whichever controller holds it. Unlike FetchRequest, the
+// result reflects the controller rather than the record's Type field, so a mislabelled record is
+// still deleted from the right place.
+func (c *Client) findRequest(ctx context.Context, partitionKey, rangeKey string) (*Request, error) {
+	input := FetchRequestInput{PartitionKey: partitionKey, RangeKey: rangeKey}
+
+	infoRequest, err := c.FetchInfoRequest(ctx, input)
+	if err == nil {
+		return &Request{Type: TypeInfoRequest, Info: infoRequest}, nil
+	}
+	if !errors.Is(err, ErrRequestNotFound) {
+		return nil, err
+	}
+
+	deleteRequest, err := c.FetchDeleteRequest(ctx, input)
+	if err != nil {
+		return nil, err
+	}
+	return &Request{Type: TypeDeleteRequest, Delete: deleteRequest}, nil
+}
+
+// rangeKey returns the RangeKey of whichever request r holds
+func (r *Request) rangeKey() string {
+	if r.Delete != nil {
+		return r.Delete.RangeKey
+	}
+	return r.Info.RangeKey
+}
@@ -0,0 +1,346 @@
+package gdprclient
+
+import (
+	"context"
+	"errors"
+	"io"
+	"math/rand"
+	"net"
+	"net/http"
+	"net/url"
+	"reflect"
+	"strings"
+	"sync/atomic"
+	"testing"
+	"time"
+)
+
+// roundTripFunc is an http.RoundTripper backed by a function
+type roundTripFunc func(*http.Request) (*http.Response, error)
+
+func (f roundTripFunc) RoundTrip(req *http.Request) (*http.Response, error) {
+	return f(req)
+}
+
+// stubResponse returns a response with the given status and JSON body
+func stubResponse(req *http.Request, statusCode int, body string) *http.Response {
+	return &http.Response{
+		StatusCode: statusCode,
+		Header:     http.Header{"Content-Type": []string{MediaTypeJSON}},
+		Body:       io.NopCloser(strings.NewReader(body)),
+		Request:    req,
+	}
+}
+
+// newStubClient returns a client that sends every attempt to transport
+func newStubClient(t *testing.T, transport http.RoundTripper, options ...ClientOption) *Client {
+	t.Helper()
+	client, err := NewClient("https://gdpr.example.com", "test-key", append([]ClientOption{WithTransport(transport)}, options...)...)
+	if err != nil {
+		t.Fatalf("NewClient: %v", err)
+	}
+	return client
+}
+
+// fastRetries retries quickly so tests do not wait on real backoffs
+var fastRetries = RetryPolicy{MaxRetries: 3, InitialBackoff: time.Millisecond, MaxBackoff: time.Millisecond, BackoffFactor: 1}
+
+func TestDeleteControllerRetriesUnavailable(t *testing.T) {
+	const record = `{"statusCode":200,"data":{"partition_key":"p","range_key":"r","type":"DELETE_REQUEST","status":"PENDING"}}`
+
+	tests := []struct {
+		name string
+		call func(ctx context.Context, client *Client) error
+	}{
+		{"FetchDeleteRequest", func(ctx context.Context, client *Client) error {
+			_, err := client.FetchDeleteRequest(ctx, FetchRequestInput{PartitionKey: "p", RangeKey: "r"})
+			return err
+		}},
+		{"UpdateDeleteRequest", func(ctx context.Context, client *Client) error {
+			_, err := client.UpdateDeleteRequest(ctx, UpdateRequestInput{PartitionKey: "p", RangeKey: "r", Type: TypeDeleteRequest})
+			return err
+		}},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			var calls int32
+			transport := roundTripFunc(func(req *http.Request) (*http.Response, error) {
+				if atomic.AddInt32(&calls, 1) <= 2 {
+					return stubResponse(req, http.StatusServiceUnavailable, `{"statusCode":503}`), nil
+				}
+				return stubResponse(req, http.StatusOK, record), nil
+			})
+			client := newStubClient(t, transport, WithRetryPolicy(fastRetries))
+
+			var md ResponseMetadata
+			ctx := CaptureResponseMetadata(context.Background(), &md)
+			if err := tt.call(ctx, client); err != nil {
+				t.Fatalf("%s: %v", tt.name, err)
+			}
+			if got := atomic.LoadInt32(&calls); got != 3 {
+				t.Errorf("transport called %d times, want 3", got)
+			}
+			if md.Attempts != 3 {
+				t.Errorf("ResponseMetadata.Attempts = %d, want 3", md.Attempts)
+			}
+		})
+	}
+}
+
+func TestRetryStopsWhenContextCancelled(t *testing.T) {
+	ctx, cancel := context.WithCancel(context.Background())
+	defer cancel()
+
+	var calls int32
+	transport := roundTripFunc(func(req *http.Request) (*http.Response, error) {
+		atomic.AddInt32(&calls, 1)
+		cancel()
+		return stubResponse(req, http.StatusServiceUnavailable, `{"statusCode":503}`), nil
+	})
+	// A zero backoff lets the loop reach its next iteration with the context already cancelled
+	client := newStubClient(t, transport, WithRetryPolicy(RetryPolicy{MaxRetries: 5, BackoffFactor: 1}))
+
+	_, err := client.FetchInfoRequest(ctx, FetchRequestInput{PartitionKey: "p", RangeKey: "r"})
+	if !errors.Is(err, context.Canceled) {
+		t.Fatalf("err = %v, want context.Canceled", err)
+	}
+	if got := atomic.LoadInt32(&calls); got != 1 {
+		t.Errorf("transport called %d times, want 1", got)
+	}
+}
+
+func TestShouldRetryDNSErrors(t *testing.T) {
+	// dial wraps err the way net/http reports a failed connection attempt
+	dial := func(err error) error {
+		return &url.Error{Op: "Post", URL: "https://gdpr.example.com", Err: &net.OpError{Op: "dial", Net: "tcp", Err: err}}
+	}
+
+	tests := []struct {
+		name string
+		err  error
+		want bool
+	}{
+		{"temporary", &net.DNSError{Err: "server misbehaving", Name: "gdpr.example.com", IsTemporary: true}, true},
+		{"timeout", &net.DNSError{Err: "i/o timeout", Name: "gdpr.example.com", IsTimeout: true}, true},
+		{"not found", &net.DNSError{Err: "no such host", Name: "gdpr.example.com", IsNotFound: true}, false},
+		{"permanent", &net.DNSError{Err: "server misbehaving", Name: "gdpr.example.com"}, false},
+		{"wrapped temporary", dial(&net.DNSError{Err: "server misbehaving", Name: "gdpr.example.com", IsTemporary: true}), true},
+		{"wrapped not found", dial(&net.DNSError{Err: "no such host", Name: "gdpr.example.com", IsNotFound: true}), false},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if got := ShouldRetry(0, tt.err); got != tt.want {
+				t.Errorf("ShouldRetry(0, %v) = %v, want %v", tt.err, got, tt.want)
+			}
+		})
+	}
+}
+
+func TestBackoffReturnsPromptlyWhenCancelled(t *testing.T) {
+	ctx, cancel := context.WithCancel(context.Background())
+	defer cancel()
+
+	transport := roundTripFunc(func(req *http.Request) (*http.Response, error) {
+		return stubResponse(req, http.StatusServiceUnavailable, `{"statusCode":503}`), nil
+	})
+	client := newStubClient(t, transport, WithRetryPolicy(RetryPolicy{MaxRetries: 1, InitialBackoff: time.Hour, MaxBackoff: time.Hour, BackoffFactor: 1, JitterStrategy: JitterNone}))
+
+	time.AfterFunc(20*time.Millisecond, cancel)
+	start := time.Now()
+	_, err := client.FetchInfoRequest(ctx, FetchRequestInput{PartitionKey: "p", RangeKey: "r"})
+	if !errors.Is(err, context.Canceled) {
+		t.Fatalf("err = %v, want context.Canceled", err)
+	}
+	if elapsed := time.Since(start); elapsed > time.Second {
+		t.Errorf("call returned after %v, want it to stop waiting once cancelled", elapsed)
+	}
+}
+
+// brokenBody returns some bytes and then fails, like a connection dropped mid-body
+type brokenBody struct {
+	sent bool
+}
+
+func (b *brokenBody) Read(p []byte) (int, error) {
+	if b.sent {
+		return 0, io.ErrUnexpectedEOF
+	}
+	b.sent = true
+	return copy(p, `{"statusCode":200,"da`), nil
+}
+
+func (b *brokenBody) Close() error {
+	return nil
+}
+
+func TestBodyReadFailureSharesRetryLoop(t *testing.T) {
+	const record = `{"statusCode":200,"data":{"partition_key":"p","range_key":"r"}}`
+
+	tests := []struct {
+		name       string
+		create     bool
+		failures   int32 // attempts whose body breaks before the rest succeed
+		maxRetries int
+		wantCalls  int32
+		wantErr    bool
+	}{
+		{"recovers", false, 2, 3, 3, false},
+		{"gives up after MaxRetries", false, 10, 1, 2, true},
+		{"create is not resent", true, 10, 3, 1, true},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			var calls int32
+			transport := roundTripFunc(func(req *http.Request) (*http.Response, error) {
+				if atomic.AddInt32(&calls, 1) <= tt.failures {
+					resp := stubResponse(req, http.StatusOK, "")
+					resp.Body = &brokenBody{}
+					return resp, nil
+				}
+				return stubResponse(req, http.StatusOK, record), nil
+			})
+			policy := fastRetries
+			policy.MaxRetries = tt.maxRetries
+			client := newStubClient(t, transport, WithRetryPolicy(policy))
+
+			var md ResponseMetadata
+			ctx := CaptureResponseMetadata(context.Background(), &md)
+			var err error
+			if tt.create {
+				_, err = client.CreateInfoRequest(ctx, CreateInfoRequestInput{PartitionKey: "p", Type: TypeInfoRequest, CreatedBy: "test"})
+			} else {
+				_, err = client.FetchInfoRequest(ctx, FetchRequestInput{PartitionKey: "p", RangeKey: "r"})
+			}
+
+			if (err != nil) != tt.wantErr {
+				t.Fatalf("err = %v, want error: %v", err, tt.wantErr)
+			}
+			if got := atomic.LoadInt32(&calls); got != tt.wantCalls {
+				t.Errorf("transport called %d times, want %d", got, tt.wantCalls)
+			}
+			if md.Attempts != int(tt.wantCalls) {
+				t.Errorf("ResponseMetadata.Attempts = %d, want %d", md.Attempts, tt.wantCalls)
+			}
+		})
+	}
+}
+
+func TestRetryAfter(t *testing.T) {
+	now := time.Date(2025, 3, 24, 12, 0, 0, 0, time.UTC)
+
+	tests := []struct {
+		name       string
+		retryAfter string
+		want       time.Duration
+	}{
+		{"delta seconds", "2", 2 * time.Second},
+		{"HTTP date", now.Add(3 * time.Second).Format(http.TimeFormat), 3 * time.Second},
+		{"clamped to MaxBackoff", "60", 5 * time.Second},
+		{"never shorter than the backoff", "0", 100 * time.Millisecond},
+		{"unparseable", "soon", 100 * time.Millisecond},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			var calls int32
+			transport := roundTripFunc(func(req *http.Request) (*http.Response, error) {
+				if atomic.AddInt32(&calls, 1) == 1 {
+					resp := stubResponse(req, http.StatusTooManyRequests, "")
+					resp.Header.Set("Retry-After", tt.retryAfter)
+					return resp, nil
+				}
+				return stubResponse(req, http.StatusOK, `{"statusCode":200,"data":{"partition_key":"p","range_key":"r"}}`), nil
+			})
+			policy := RetryPolicy{MaxRetries: 1, InitialBackoff: 100 * time.Millisecond, MaxBackoff: 5 * time.Second, BackoffFactor: 2, JitterStrategy: JitterNone}
+			clock := &fakeClock{now: now, autoFire: true}
+			client := newStubClient(t, transport, WithRetryPolicy(policy), WithClock(clock))
+
+			if _, err := client.FetchInfoRequest(context.Background(), FetchRequestInput{PartitionKey: "p", RangeKey: "r"}); err != nil {
+				t.Fatalf("FetchInfoRequest: %v", err)
+			}
+			if want := []time.Duration{tt.want}; !reflect.DeepEqual(clock.waits, want) {
+				t.Errorf("waited %v, want %v", clock.waits, want)
+			}
+		})
+	}
+}
+
+func TestRetryAfterDoesNotFeedDecorrelatedBackoff(t *testing.T) {
+	// Two failures then success; the first failure may carry Retry-After
+	waits := func(retryAfter string) []time.Duration {
+		var calls int32
+		transport := roundTripFunc(func(req *http.Request) (*http.Response, error) {
+			switch atomic.AddInt32(&calls, 1) {
+			case 1:
+				resp := stubResponse(req, http.StatusTooManyRequests, "")
+				if retryAfter != "" {
+					resp.Header.Set("Retry-After", retryAfter)
+				}
+				return resp, nil
+			case 2:
+				return stubResponse(req, http.StatusServiceUnavailable, ""), nil
+			}
+			return stubResponse(req, http.StatusOK, `{"statusCode":200,"data":{"partition_key":"p","range_key":"r"}}`), nil
+		})
+		policy := RetryPolicy{MaxRetries: 2, InitialBackoff: 100 * time.Millisecond, MaxBackoff: 10 * time.Second, JitterStrategy: JitterDecorrelated}
+		clock := &fakeClock{now: time.Date(2025, 3, 24, 12, 0, 0, 0, time.UTC), autoFire: true}
+		client := newStubClient(t, transport, WithRetryPolicy(policy), WithClock(clock), WithRandSource(rand.NewSource(1)))
+
+		if _, err := client.FetchInfoRequest(context.Background(), FetchRequestInput{PartitionKey: "p", RangeKey: "r"}); err != nil {
+			t.Fatalf("FetchInfoRequest: %v", err)
+		}
+		return clock.waits
+	}
+
+	plain, throttled := waits(""), waits("8")
+	if len(plain) != 2 || len(throttled) != 2 {
+		t.Fatalf("waited %v and %v, want two waits each", plain, throttled)
+	}
+	if throttled[0] != 8*time.Second {
+		t.Errorf("first wait = %v, want the 8s Retry-After", throttled[0])
+	}
+	if throttled[1] != plain[1] {
+		t.Errorf("second wait = %v after Retry-After, want %v as without it", throttled[1], plain[1])
+	}
+}
+
+func TestRetryBudgetExhaustion(t *testing.T) {
+	var calls int
+	transport := roundTripFunc(func(req *http.Request) (*http.Response, error) {
+		calls++
+		return stubResponse(req, http.StatusServiceUnavailable, `{"statusCode":503,"message":"unavailable"}`), nil
+	})
+	clock := &fakeClock{now: time.Date(2025, 3, 24, 12, 0, 0, 0, time.UTC), autoFire: true}
+	client := newStubClient(t, transport, WithRetryPolicy(fastRetries), WithClock(clock), WithRetryBudget(4, time.Minute))
+
+	// Each step is one failing call; the budget of four retries is shared between them
+	steps := []struct {
+		name      string
+		advance   time.Duration
+		wantCalls int
+	}{
+		{"full budget", 0, 1 + 3},
+		{"one retry left", 0, 1 + 1},
+		{"spent", 0, 1},
+		{"half refilled", 30 * time.Second, 1 + 2},
+	}
+
+	for _, step := range steps {
+		clock.advance(step.advance)
+		calls = 0
+		_, err := client.FetchInfoRequest(context.Background(), FetchRequestInput{PartitionKey: "p", RangeKey: "r"})
+		var apiErr *APIError
+		if !errors.As(err, &apiErr) || apiErr.StatusCode != http.StatusServiceUnavailable {
+			t.Fatalf("%s: err = %v, want the 503 APIError", step.name, err)
+		}
+		if calls != step.wantCalls {
+			t.Errorf("%s: transport called %d times, want %d", step.name, calls, step.wantCalls)
+		}
+	}
+	if got := client.Stats().Retries; got != 3+1+2 {
+		t.Errorf("Stats().Retries = %d, want %d", got, 3+1+2)
+	}
+}
@@ -0,0 +1,379 @@
+package gdprclient
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"io"
+	"net/http"
+	"reflect"
+	"strings"
+	"sync"
+	"testing"
+	"time"
+)
+
+func TestContentLengthOnEveryMethod(t *testing.T) {
+	// Send updates as PUT and deletes as DELETE so every method carries a body
+	resolver := EndpointResolverFunc(func(controller, action string) Endpoint {
+		endpoint := PathEndpoints.Resolve(controller, action)
+		switch action {
+		case "update":
+			endpoint.Method = http.MethodPut
+		case "delete":
+			endpoint.Method = http.MethodDelete
+		}
+		return endpoint
+	})
+
+	type sent struct {
+		method        string
+		contentLength int64
+		body          string
+	}
+	var requests []sent
+	transport := roundTripFunc(func(req *http.Request) (*http.Response, error) {
+		body, err := io.ReadAll(req.Body)
+		if err != nil {
+			return nil, err
+		}
+		requests = append(requests, sent{req.Method, req.ContentLength, string(body)})
+		return stubResponse(req, http.StatusOK, `{"statusCode":200,"data":{"partition_key":"p","range_key":"r"}}`), nil
+	})
+	client := newStubClient(t, transport, WithEndpointResolver(resolver))
+
+	ctx := context.Background()
+	if _, err := client.CreateInfoRequest(ctx, CreateInfoRequestInput{PartitionKey: "p", Type: TypeInfoRequest, CreatedBy: "test"}); err != nil {
+		t.Fatalf("CreateInfoRequest: %v", err)
+	}
+	if _, err := client.UpdateInfoRequest(ctx, UpdateRequestInput{PartitionKey: "p", RangeKey: "r", Status: StatusComplete}); err != nil {
+		t.Fatalf("UpdateInfoRequest: %v", err)
+	}
+	if _, err := client.DeleteRequest(ctx, DeleteRequestInput{PartitionKey: "p", RangeKey: "r"}); err != nil {
+		t.Fatalf("DeleteRequest: %v", err)
+	}
+	// An action with no input sends "{}", which still needs a Content-Length
+	if err := client.Do(ctx, "", "ping", nil, nil); err != nil {
+		t.Fatalf("Do: %v", err)
+	}
+
+	wantMethods := []string{http.MethodPost, http.MethodPut, http.MethodDelete, http.MethodPost}
+	if len(requests) != len(wantMethods) {
+		t.Fatalf("sent %d requests, want %d", len(requests), len(wantMethods))
+	}
+	for i, req := range requests {
+		if req.method != wantMethods[i] {
+			t.Errorf("request %d method = %s, want %s", i, req.method, wantMethods[i])
+		}
+		if req.contentLength <= 0 || req.contentLength != int64(len(req.body)) {
+			t.Errorf("%s %s: ContentLength = %d, want %d", req.method, req.body, req.contentLength, len(req.body))
+		}
+	}
+	if requests[3].body != "{}" {
+		t.Errorf("empty input sent %q, want {}", requests[3].body)
+	}
+}
+
+// recordingLogger keeps the messages it receives
+type recordingLogger struct {
+	mu     sync.Mutex
+	debugs []string
+	infos  []string
+}
+
+func (l *recordingLogger) Debugf(format string, v ...interface{}) {
+	l.mu.Lock()
+	defer l.mu.Unlock()
+	l.debugs = append(l.debugs, fmt.Sprintf(format, v...))
+}
+
+func (l *recordingLogger) Infof(format string, v ...interface{}) {
+	l.mu.Lock()
+	defer l.mu.Unlock()
+	l.infos = append(l.infos, fmt.Sprintf(format, v...))
+}
+
+// curls returns the curl commands logged by WithCurlOnError
+func (l *recordingLogger) curls() []string {
+	l.mu.Lock()
+	defer l.mu.Unlock()
+	var curls []string
+	for _, info := range l.infos {
+		if i := strings.Index(info, "reproduce with: "); i >= 0 {
+			curls = append(curls, info[i+len("reproduce with: "):])
+		}
+	}
+	return curls
+}
+
+func TestCurlOnError(t *testing.T) {
+	tests := []struct {
+		name     string
+		status   int
+		body     string
+		wantCurl bool
+	}{
+		{"success", http.StatusOK, `{"statusCode":200,"data":{"partition_key":"p","range_key":"r"}}`, false},
+		{"HTTP error", http.StatusNotFound, `{"statusCode":404,"message":"not found"}`, true},
+		{"envelope error", http.StatusOK, `{"statusCode":500,"message":"boom"}`, true},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			transport := roundTripFunc(func(req *http.Request) (*http.Response, error) {
+				return stubResponse(req, tt.status, tt.body), nil
+			})
+			logger := &recordingLogger{}
+			client := newStubClient(t, transport, WithCurlOnError(), WithLogger(logger), WithRequestInterceptor(func(req *http.Request) error {
+				req.Header.Set("X-Tenant", "acme")
+				return nil
+			}))
+
+			ctx := ContextWithCorrelationID(context.Background(), "corr-1")
+			client.FetchInfoRequest(ctx, FetchRequestInput{PartitionKey: "p", RangeKey: "r"})
+
+			curls := logger.curls()
+			if !tt.wantCurl {
+				if len(curls) != 0 {
+					t.Errorf("logged %q, want no curl command", curls)
+				}
+				return
+			}
+			if len(curls) != 1 {
+				t.Fatalf("logged %d curl commands, want 1: %q", len(curls), curls)
+			}
+			for _, want := range []string{"X-Tenant: acme", CorrelationIDHeader + ": corr-1", "X-Api-Key: " + redacted, `"partition_key":"p"`} {
+				if !strings.Contains(curls[0], want) {
+					t.Errorf("curl %s does not contain %q", curls[0], want)
+				}
+			}
+			if strings.Contains(curls[0], "test-key") {
+				t.Errorf("curl %s leaks the API key", curls[0])
+			}
+		})
+	}
+}
+
+// fallbackMetrics records the fallback API keys reported through APIKeyObserver
+type fallbackMetrics struct {
+	fallbacks []int
+}
+
+func (m *fallbackMetrics) ObserveRequest(operation string, statusCode int, duration time.Duration, attempts int) {
+}
+
+func (m *fallbackMetrics) ObserveAPIKeyFallback(operation string, index int) {
+	m.fallbacks = append(m.fallbacks, index)
+}
+
+func TestFallbackAPIKeys(t *testing.T) {
+	const record = `{"statusCode":200,"data":{"partition_key":"p","range_key":"r"}}`
+
+	tests := []struct {
+		name          string
+		inputKey      string
+		accepted      string // the key the service accepts, or none
+		rejection     int
+		wantKeys      []string
+		wantFallbacks []int
+	}{
+		{"primary accepted", "", "test-key", http.StatusUnauthorized, []string{"test-key"}, nil},
+		{"first fallback", "", "next-1", http.StatusUnauthorized, []string{"test-key", "next-1"}, []int{1}},
+		{"second fallback after 403", "", "next-2", http.StatusForbidden, []string{"test-key", "next-1", "next-2"}, []int{2}},
+		{"all rejected", "", "", http.StatusUnauthorized, []string{"test-key", "next-1", "next-2"}, nil},
+		{"input key has no fallbacks", "tenant-key", "", http.StatusUnauthorized, []string{"tenant-key"}, nil},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			var keys []string
+			transport := roundTripFunc(func(req *http.Request) (*http.Response, error) {
+				key := req.Header.Get("X-Api-Key")
+				keys = append(keys, key)
+				if key != tt.accepted {
+					return stubResponse(req, tt.rejection, fmt.Sprintf(`{"statusCode":%d,"message":"rejected"}`, tt.rejection)), nil
+				}
+				return stubResponse(req, http.StatusOK, record), nil
+			})
+			metrics := &fallbackMetrics{}
+			client := newStubClient(t, transport, WithFallbackAPIKeys([]string{"next-1", "next-2"}), WithMetrics(metrics))
+
+			_, err := client.FetchInfoRequest(context.Background(), FetchRequestInput{PartitionKey: "p", RangeKey: "r", ApiKey: tt.inputKey})
+
+			if !reflect.DeepEqual(keys, tt.wantKeys) {
+				t.Errorf("sent keys %q, want %q", keys, tt.wantKeys)
+			}
+			if !reflect.DeepEqual(metrics.fallbacks, tt.wantFallbacks) {
+				t.Errorf("observed fallbacks %v, want %v", metrics.fallbacks, tt.wantFallbacks)
+			}
+
+			if tt.accepted != "" {
+				if err != nil {
+					t.Fatalf("FetchInfoRequest: %v", err)
+				}
+				return
+			}
+			var apiErr *APIError
+			if !errors.As(err, &apiErr) || apiErr.StatusCode != tt.rejection || !errors.Is(err, ErrUnauthorized) {
+				t.Errorf("err = %v, want the last key's %d as an APIError", err, tt.rejection)
+			}
+		})
+	}
+}
+
+func TestEndpointURLs(t *testing.T) {
+	tests := []struct {
+		name       string
+		resolver   EndpointResolver
+		controller string
+		want       string
+	}{
+		{"query, info", QueryEndpoints, "", "https://gdpr.example.com/gdpr?action=create"},
+		{"query, delete", QueryEndpoints, "delete", "https://gdpr.example.com/gdpr?action=create&controller=delete"},
+		{"path, info", PathEndpoints, "", "https://gdpr.example.com/gdpr/create"},
+		{"path, delete", PathEndpoints, "delete", "https://gdpr.example.com/gdpr/delete/create"},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			client := newStubClient(t, nil, WithEndpointResolver(tt.resolver))
+			if method, target := client.endpoint(tt.controller, "create"); method != http.MethodPost || target != tt.want {
+				t.Errorf("endpoint = %s %s, want POST %s", method, target, tt.want)
+			}
+		})
+	}
+}
+
+func TestSigV4GetVanilla(t *testing.T) {
+	// The get-vanilla case of the AWS Signature Version 4 test suite
+	signer := &sigV4Signer{
+		credentials: func(context.Context) (AWSCredentials, error) {
+			return AWSCredentials{AccessKeyID: "AKIDEXAMPLE", SecretAccessKey: "wJalrXUtnFEMI/K7MDENG+bPxRfiCYEXAMPLEKEY"}, nil
+		},
+		region:  "us-east-1",
+		service: "service",
+	}
+	req, err := http.NewRequest(http.MethodGet, "https://example.amazonaws.com/", nil)
+	if err != nil {
+		t.Fatalf("NewRequest: %v", err)
+	}
+
+	if err := signer.sign(req, time.Date(2015, 8, 30, 12, 36, 0, 0, time.UTC)); err != nil {
+		t.Fatalf("sign: %v", err)
+	}
+
+	const want = "AWS4-HMAC-SHA256 Credential=AKIDEXAMPLE/20150830/us-east-1/service/aws4_request, SignedHeaders=host;x-amz-date, Signature=5fa00fa31553b73ebf1942676e86291e8372ff2a2260956d9b8aae1d763fbf31"
+	if got := req.Header.Get("Authorization"); got != want {
+		t.Errorf("Authorization = %q, want %q", got, want)
+	}
+	if got := req.Header.Get("X-Amz-Date"); got != "20150830T123600Z" {
+		t.Errorf("X-Amz-Date = %q, want 20150830T123600Z", got)
+	}
+}
+
+func TestBodyLogging(t *testing.T) {
+	const response = `{"statusCode":200,"data":{"partition_key":"p","range_key":"r","created_by":"alice@example.com"}}`
+
+	tests := []struct {
+		name  string
+		limit int
+		want  []string // substrings of the logged request and response bodies
+	}{
+		{"redacted", 1000, []string{
+			`create request body: {"created_by":"REDACTED","partition_key":"p","type":"INFO_REQUEST"}`,
+			`create response body: {"data":{"created_by":"REDACTED","partition_key":"p","range_key":"r"},"statusCode":200}`,
+		}},
+		{"truncated after redaction", 20, []string{
+			`create request body: {"created_by":"REDAC... (47 more bytes)`,
+			`create response body: {"data":{"created_by... (67 more bytes)`,
+		}},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			transport := roundTripFunc(func(req *http.Request) (*http.Response, error) {
+				return stubResponse(req, http.StatusOK, response), nil
+			})
+			logger := &recordingLogger{}
+			client := newStubClient(t, transport, WithLogger(logger), WithBodyLogging(tt.limit, RedactJSONFields("created_by")))
+
+			if _, err := client.CreateInfoRequest(context.Background(), CreateInfoRequestInput{PartitionKey: "p", Type: TypeInfoRequest, CreatedBy: "alice@example.com"}); err != nil {
+				t.Fatalf("CreateInfoRequest: %v", err)
+			}
+
+			logged := strings.Join(logger.debugs, "\n")
+			if strings.Contains(logged, "alice") {
+				t.Errorf("logged bodies leak the redacted field:\n%s", logged)
+			}
+			for _, want := range tt.want {
+				if !strings.Contains(logged, want) {
+					t.Errorf("logged bodies do not contain %s:\n%s", want, logged)
+				}
+			}
+		})
+	}
+}
+
+func TestRedactJSONFieldsReplacesInvalidJSON(t *testing.T) {
+	if got := string(RedactJSONFields("created_by")([]byte("<html>alice</html>"))); got != redacted {
+		t.Errorf("redacted body = %q, want %q", got, redacted)
+	}
+}
+
+func TestCSVNegotiation(t *testing.T) {
+	const csvBody = "status,range_key,partition_key,extra\nPENDING,1,p,x\nCOMPLETE,2,p,y\n"
+
+	tests := []struct {
+		name        string
+		contentType string
+		body        string
+		options     []ClientOption
+		wantErr     bool
+	}{
+		{"CSV", MediaTypeCSV + "; charset=utf-8", csvBody, nil, false},
+		{"JSON despite Accept", MediaTypeJSON, `{"statusCode":200,"data":{"results":[{"partition_key":"p","range_key":"1","status":"PENDING"},{"partition_key":"p","range_key":"2","status":"COMPLETE"}],"lastRangeKey":"2"}}`, nil, false},
+		{"strict rejects unknown column", MediaTypeCSV, csvBody, []ClientOption{WithStrictDecoding()}, true},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			var accepts []string
+			transport := roundTripFunc(func(req *http.Request) (*http.Response, error) {
+				accepts = append(accepts, req.Header.Get("Accept"))
+				if req.URL.Query().Get("action") != "fetchAll" {
+					return stubResponse(req, http.StatusOK, `{"statusCode":200,"data":{"partition_key":"p","range_key":"1"}}`), nil
+				}
+				resp := stubResponse(req, http.StatusOK, tt.body)
+				resp.Header.Set("Content-Type", tt.contentType)
+				resp.Header.Set(LastRangeKeyHeader, "2")
+				return resp, nil
+			})
+			client := newStubClient(t, transport, append([]ClientOption{WithAccept(MediaTypeCSV)}, tt.options...)...)
+
+			page, err := client.FetchAllDeleteRequests(context.Background(), FetchAllRequestInput{PartitionKey: "p"})
+			if _, fetchErr := client.FetchDeleteRequest(context.Background(), FetchRequestInput{PartitionKey: "p", RangeKey: "1"}); fetchErr != nil {
+				t.Fatalf("FetchDeleteRequest: %v", fetchErr)
+			}
+			if want := []string{MediaTypeCSV, MediaTypeJSON}; !reflect.DeepEqual(accepts, want) {
+				t.Errorf("sent Accept %q, want %q", accepts, want)
+			}
+
+			if tt.wantErr {
+				if err == nil {
+					t.Errorf("FetchAllDeleteRequests = %+v, want an error", page)
+				}
+				return
+			}
+			if err != nil {
+				t.Fatalf("FetchAllDeleteRequests: %v", err)
+			}
+			want := []DeleteRequest{
+				{PartitionKey: "p", RangeKey: "1", Status: StatusPending},
+				{PartitionKey: "p", RangeKey: "2", Status: StatusComplete},
+			}
+			if !reflect.DeepEqual(page.Results, want) || page.LastRangeKey != "2" {
+				t.Errorf("page = %+v, want %+v after 2", page, want)
+			}
+		})
+	}
+}
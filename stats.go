@@ -0,0 +1,59 @@
+package gdprclient
+
+import (
+	"errors"
+	"sync/atomic"
+)
+
+// Stats is a snapshot of the calls a Client has sent to the service since it was created. Each
+// call to a controller action counts once, however many attempts or fallback API keys it took,
+// and succeeds or fails with the error it finally returned, including envelope errors and errors
+// decoding the response. Methods such as FetchRequest or DeleteAllByPartitionKey make several
+// calls. Calls stopped by the circuit breaker or Close, and results served from the cache, are
+// not counted.
+type Stats struct {
+	Requests  int64 // Calls sent
+	Successes int64 // Calls that returned no error
+	Failures  int64 // Calls that returned an error
+	Retries   int64 // Attempts sent after the first by the retry policy, across all calls
+}
+
+// clientStats holds the counters behind Stats, updated atomically
+type clientStats struct {
+	requests  int64
+	successes int64
+	failures  int64
+	retries   int64
+}
+
+// Stats returns the client's counters. Each counter is read atomically, but calls finishing
+// during the read may be reflected in some counters and not others.
+func (c *Client) Stats() Stats {
+	return Stats{
+		Requests:  atomic.LoadInt64(&c.stats.requests),
+		Successes: atomic.LoadInt64(&c.stats.successes),
+		Failures:  atomic.LoadInt64(&c.stats.failures),
+		Retries:   atomic.LoadInt64(&c.stats.retries),
+	}
+}
+
+// record counts a finished call by the error it returned
+func (s *clientStats) record(err error) {
+	if errors.Is(err, ErrCircuitOpen) || errors.Is(err, ErrClientClosed) {
+		return
+	}
+
+	atomic.AddInt64(&s.requests, 1)
+	if err != nil {
+		atomic.AddInt64(&s.failures, 1)
+	} else {
+		atomic.AddInt64(&s.successes, 1)
+	}
+}
+
+// retried counts the attempts after the first of one pass through the retry loop
+func (s *clientStats) retried(attempts int) {
+	if attempts > 1 {
+		atomic.AddInt64(&s.retries, int64(attempts-1))
+	}
+}
@@ -0,0 +1,71 @@
+package gdprclient
+
+import (
+	"context"
+	"net/http"
+	"testing"
+)
+
+func TestStatsCountsEachCallOnce(t *testing.T) {
+	const record = `{"statusCode":200,"data":{"partition_key":"p","range_key":"r"}}`
+
+	tests := []struct {
+		name    string
+		opts    []ClientOption
+		respond func(call int, req *http.Request) *http.Response
+		want    Stats
+	}{
+		{
+			name: "success",
+			respond: func(call int, req *http.Request) *http.Response {
+				return stubResponse(req, http.StatusOK, record)
+			},
+			want: Stats{Requests: 1, Successes: 1},
+		},
+		{
+			name: "body read failures",
+			opts: []ClientOption{WithRetryPolicy(fastRetries)},
+			respond: func(call int, req *http.Request) *http.Response {
+				resp := stubResponse(req, http.StatusOK, "")
+				resp.Body = &brokenBody{}
+				return resp
+			},
+			want: Stats{Requests: 1, Failures: 1, Retries: 3},
+		},
+		{
+			name: "envelope error on HTTP 200",
+			respond: func(call int, req *http.Request) *http.Response {
+				return stubResponse(req, http.StatusOK, `{"statusCode":500,"message":"boom"}`)
+			},
+			want: Stats{Requests: 1, Failures: 1},
+		},
+		{
+			name: "fallback API key",
+			opts: []ClientOption{WithFallbackAPIKeys([]string{"next-key"})},
+			respond: func(call int, req *http.Request) *http.Response {
+				if call == 1 {
+					return stubResponse(req, http.StatusUnauthorized, `{"statusCode":401,"message":"unauthorized"}`)
+				}
+				return stubResponse(req, http.StatusOK, record)
+			},
+			want: Stats{Requests: 1, Successes: 1},
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			var calls int
+			transport := roundTripFunc(func(req *http.Request) (*http.Response, error) {
+				calls++
+				return tt.respond(calls, req), nil
+			})
+			client := newStubClient(t, transport, tt.opts...)
+
+			client.FetchInfoRequest(context.Background(), FetchRequestInput{PartitionKey: "p", RangeKey: "r"})
+
+			if got := client.Stats(); got != tt.want {
+				t.Errorf("Stats() = %+v, want %+v", got, tt.want)
+			}
+		})
+	}
+}
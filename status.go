@@ -0,0 +1,126 @@
+package gdprclient
+
+import (
+	"context"
+	"fmt"
+)
+
+// MarkComplete sets the status of a request to StatusComplete and returns the updated request.
+// requestType is TypeInfoRequest or TypeDeleteRequest and selects the update endpoint; no other
+// field of the request is changed.
+func (c *Client) MarkComplete(ctx context.Context, requestType, partitionKey, rangeKey string) (*Request, error) {
+	return c.setStatus(ctx, requestType, partitionKey, rangeKey, StatusComplete)
+}
+
+// MarkFailed sets the status of a request to StatusFailed, as MarkComplete does for StatusComplete
+func (c *Client) MarkFailed(ctx context.Context, requestType, partitionKey, rangeKey string) (*Request, error) {
+	return c.setStatus(ctx, requestType, partitionKey, rangeKey, StatusFailed)
+}
+
+// MarkDeleted sets the status of a request to StatusDeleted, as MarkComplete does for StatusComplete.
+// The record is kept; use DeleteInfoRequest or DeleteRequest to remove it.
+func (c *Client) MarkDeleted(ctx context.Context, requestType, partitionKey, rangeKey string) (*Request, error) {
+	return c.setStatus(ctx, requestType, partitionKey, rangeKey, StatusDeleted)
+}
+
+// setStatus updates only the status of a request through the update endpoint for requestType
+func (c *Client) setStatus(ctx context.Context, requestType, partitionKey, rangeKey, status string) (*Request, error) {
+	if err := oneOf(FieldType, requestType, validTypes, true); err != nil {
+		return nil, err
+	}
+
+	input := UpdateRequestInput{
+		PartitionKey: partitionKey,
+		RangeKey:     rangeKey,
+		Status:       status,
+	}
+	if requestType == TypeDeleteRequest {
+		deleteRequest, err := c.UpdateDeleteRequest(ctx, input)
+		if err != nil {
+			return nil, err
+		}
+		return &Request{Type: requestType, Delete: deleteRequest}, nil
+	}
+
+	infoRequest, err := c.UpdateInfoRequest(ctx, input)
+	if err != nil {
+		return nil, err
+	}
+	return &Request{Type: requestType, Info: infoRequest}, nil
+}
+
+// statusTransitions lists the statuses each known status may change to. A pending request may
+// move to any other status; a completed or failed request may only be marked deleted, and a
+// deleted request only leaves that status through RestoreInfoRequest or RestoreRequest.
+var statusTransitions = map[string][]string{
+	StatusPending:  {StatusComplete, StatusFailed, StatusDeleted},
+	StatusComplete: {StatusDeleted},
+	StatusFailed:   {StatusDeleted},
+	StatusDeleted:  {},
+}
+
+// reachable reports whether a request with status from can later have status to, following
+// statusTransitions. A status the client does not know may lead anywhere.
+func reachable(from, to string) bool {
+	seen := map[string]bool{from: true}
+	queue := []string{from}
+	for len(queue) > 0 {
+		status := queue[0]
+		queue = queue[1:]
+
+		next, known := statusTransitions[status]
+		if !known {
+			return true
+		}
+		for _, n := range next {
+			if n == to {
+				return true
+			}
+			if !seen[n] {
+				seen[n] = true
+				queue = append(queue, n)
+			}
+		}
+	}
+	return false
+}
+
+// WithStatusTransitionCheck makes update methods fetch the request before a status change and
+// return an error wrapping ErrInvalidTransition, without updating, if the new status may not follow
+// the current one, e.g. StatusComplete back to StatusPending. It costs one fetch per status change
+// and is off by default. A current status the client does not know, or none, is not checked.
+func WithStatusTransitionCheck() ClientOption {
+	return func(c *Client) {
+		c.checkTransitions = true
+	}
+}
+
+// checkTransition fetches the current status of the request an update targets and returns an
+// error wrapping ErrInvalidTransition if input.Status may not follow it. It does nothing unless
+// WithStatusTransitionCheck is set, or in dry-run mode, where there is no current status to read.
+// Updates that leave the status unset or unchanged pass. The check is advisory: the status can
+// still change between the fetch and the update.
+func (c *Client) checkTransition(ctx context.Context, controller string, input UpdateRequestInput) error {
+	if !c.checkTransitions || c.dryRun != nil || input.Status == "" {
+		return nil
+	}
+
+	var current struct {
+		Status string `json:"status"`
+	}
+	fetch := FetchRequestInput{PartitionKey: input.PartitionKey, RangeKey: input.RangeKey, ApiKey: input.ApiKey}
+	if err := c.do(ctx, controller, "fetch", input.ApiKey, fetch, &current); err != nil {
+		return err
+	}
+
+	allowed, known := statusTransitions[current.Status]
+	if !known || current.Status == input.Status {
+		return nil
+	}
+	for _, next := range allowed {
+		if next == input.Status {
+			return nil
+		}
+	}
+	return fmt.Errorf("%w: %s to %s", ErrInvalidTransition, current.Status, input.Status)
+}
@@ -0,0 +1,84 @@
+package gdprclient
+
+import (
+	"context"
+	"errors"
+	"net/http"
+	"sync/atomic"
+	"testing"
+)
+
+func TestStatusTransitionCheck(t *testing.T) {
+	tests := []struct {
+		name    string
+		current string
+		next    string
+		options []ClientOption
+		wantErr bool
+	}{
+		{"off by default", StatusComplete, StatusPending, nil, false},
+		{"pending to complete", StatusPending, StatusComplete, []ClientOption{WithStatusTransitionCheck()}, false},
+		{"complete back to pending", StatusComplete, StatusPending, []ClientOption{WithStatusTransitionCheck()}, true},
+		{"complete to deleted", StatusComplete, StatusDeleted, []ClientOption{WithStatusTransitionCheck()}, false},
+		{"deleted to complete", StatusDeleted, StatusComplete, []ClientOption{WithStatusTransitionCheck()}, true},
+		{"empty current status", "", StatusComplete, []ClientOption{WithStatusTransitionCheck()}, false},
+		{"unknown current status", "ARCHIVED", StatusPending, []ClientOption{WithStatusTransitionCheck()}, false},
+		{"dry run", StatusComplete, StatusPending, []ClientOption{WithStatusTransitionCheck(), WithDryRun(func(*http.Request) {})}, false},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			var fetches, updates int32
+			transport := roundTripFunc(func(req *http.Request) (*http.Response, error) {
+				if req.URL.Query().Get("action") == "fetch" {
+					atomic.AddInt32(&fetches, 1)
+					return stubResponse(req, http.StatusOK, `{"statusCode":200,"data":{"partition_key":"p","range_key":"r","status":"`+tt.current+`"}}`), nil
+				}
+				atomic.AddInt32(&updates, 1)
+				return stubResponse(req, http.StatusOK, `{"statusCode":200,"data":{"partition_key":"p","range_key":"r","status":"`+tt.next+`"}}`), nil
+			})
+			client := newStubClient(t, transport, tt.options...)
+
+			ctx := context.Background()
+			var err error
+			switch tt.next {
+			case StatusComplete:
+				_, err = client.MarkComplete(ctx, TypeDeleteRequest, "p", "r")
+			case StatusDeleted:
+				_, err = client.MarkDeleted(ctx, TypeDeleteRequest, "p", "r")
+			default:
+				_, err = client.UpdateDeleteRequest(ctx, UpdateRequestInput{PartitionKey: "p", RangeKey: "r", Status: tt.next})
+			}
+
+			if tt.wantErr {
+				if !errors.Is(err, ErrInvalidTransition) {
+					t.Fatalf("err = %v, want ErrInvalidTransition", err)
+				}
+				if atomic.LoadInt32(&updates) != 0 {
+					t.Error("update was sent despite the invalid transition")
+				}
+				return
+			}
+			if err != nil {
+				t.Fatalf("update: %v", err)
+			}
+			if len(tt.options) == 0 && atomic.LoadInt32(&fetches) != 0 {
+				t.Errorf("sent %d fetches without WithStatusTransitionCheck, want none", fetches)
+			}
+		})
+	}
+}
+
+// TestTerminalStatusesOnlyLeadToDeleted keeps TerminalStatuses in step with statusTransitions
+func TestTerminalStatusesOnlyLeadToDeleted(t *testing.T) {
+	for _, terminal := range TerminalStatuses() {
+		for status := range statusTransitions {
+			if status == terminal || status == StatusDeleted {
+				continue
+			}
+			if reachable(terminal, status) {
+				t.Errorf("terminal status %s can lead to %s", terminal, status)
+			}
+		}
+	}
+}
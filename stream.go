@@ -0,0 +1,274 @@
+package gdprclient
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"fmt"
+	"io"
+	"mime"
+	"net/http"
+	"strings"
+)
+
+// StreamAllInfoRequests fetches one page of FetchAllInfoRequests and calls fn for each result as it
+// is decoded from the response, instead of buffering the whole page. It returns the LastRangeKey
+// for the next page, empty after the last one. An error from fn stops decoding and is returned.
+// fn only sees results from a response whose envelope reports status 200: if the service sends
+// data before statusCode, the page is buffered until the status is known.
+func (c *Client) StreamAllInfoRequests(ctx context.Context, input FetchAllRequestInput, fn func(InfoRequest) error) (string, error) {
+	if err := input.Validate(); err != nil {
+		return "", err
+	}
+
+	return c.stream(ctx, "", "fetchAll", input.ApiKey, input, func(dec *json.Decoder) error {
+		var infoRequest InfoRequest
+		if err := dec.Decode(&infoRequest); err != nil {
+			return fmt.Errorf("failed to unmarshal data: %w", err)
+		}
+		return fn(infoRequest)
+	})
+}
+
+// StreamAllDeleteRequests is StreamAllInfoRequests for FetchAllDeleteRequests
+func (c *Client) StreamAllDeleteRequests(ctx context.Context, input FetchAllRequestInput, fn func(DeleteRequest) error) (string, error) {
+	if err := input.Validate(); err != nil {
+		return "", err
+	}
+
+	return c.stream(ctx, "delete", "fetchAll", input.ApiKey, input, func(dec *json.Decoder) error {
+		var deleteRequest DeleteRequest
+		if err := dec.Decode(&deleteRequest); err != nil {
+			return fmt.Errorf("failed to unmarshal data: %w", err)
+		}
+		return fn(deleteRequest)
+	})
+}
+
+// stream sends a list action and walks the response envelope token by token, calling decodeResult
+// with the decoder positioned at each element of data.results. It returns data.lastRangeKey, or an
+// empty key when data.hasMore says this is the last page.
+func (c *Client) stream(ctx context.Context, controller, action, apiKey string, body interface{}, decodeResult func(*json.Decoder) error) (string, error) {
+	lastRangeKey, err := c.streamResponse(ctx, controller, action, apiKey, body, decodeResult)
+	c.stats.record(err)
+	return lastRangeKey, err
+}
+
+// streamResponse is stream without the Stats bookkeeping
+func (c *Client) streamResponse(ctx context.Context, controller, action, apiKey string, body interface{}, decodeResult func(*json.Decoder) error) (string, error) {
+	resp, err := c.send(ctx, controller, action, apiKey, MediaTypeJSON, false, body)
+	if err != nil {
+		return "", err
+	}
+	defer resp.Body.Close()
+
+	if resp.StatusCode != http.StatusOK {
+		responseBody, err := c.readResponseBody(resp)
+		if err != nil {
+			return "", err
+		}
+		return "", newAPIError(controller, action, resp.StatusCode, responseBody)
+	}
+
+	// The transform needs the whole body, so only the decoding is incremental. A body not labelled
+	// as JSON is also read whole, so a gateway error page is reported as an APIError rather than
+	// a decoding error.
+	var r io.Reader = &maxBytesReader{r: resp.Body, remaining: c.maxResponseBytes}
+	if c.responseTransform != nil || !isJSONContentType(resp.Header.Get("Content-Type")) {
+		responseBody, err := c.readResponseBody(resp)
+		if err != nil {
+			return "", err
+		}
+		if !json.Valid(responseBody) {
+			c.logCurl(resp.Request)
+			return "", newAPIError(controller, action, resp.StatusCode, responseBody)
+		}
+		r = bytes.NewReader(responseBody)
+	}
+
+	dec := c.newStreamDecoder(r)
+	if err := expectDelim(dec, '{'); err != nil {
+		return "", err
+	}
+
+	statusCode := 0
+	message := ""
+	lastRangeKey := ""
+	var pending json.RawMessage
+	for dec.More() {
+		key, err := dec.Token()
+		if err != nil {
+			return "", fmt.Errorf("failed to unmarshal response: %w", err)
+		}
+
+		switch key {
+		case "statusCode":
+			err = dec.Decode(&statusCode)
+		case "message":
+			err = dec.Decode(&message)
+		case "data":
+			// Until statusCode is read the response may still be an error, so hold the data back
+			if statusCode == 0 {
+				err = dec.Decode(&pending)
+				break
+			}
+			if statusCode != http.StatusOK {
+				err = skipValue(dec)
+				break
+			}
+			lastRangeKey, err = streamPage(dec, decodeResult)
+		default:
+			err = skipValue(dec)
+		}
+		if err != nil {
+			return "", err
+		}
+	}
+
+	if statusCode != http.StatusOK {
+		c.logCurl(resp.Request)
+		return "", &APIError{
+			StatusCode:     statusCode,
+			ServiceMessage: message,
+			Controller:     controller,
+			Action:         action,
+		}
+	}
+
+	if pending != nil {
+		return streamPage(c.newStreamDecoder(bytes.NewReader(pending)), decodeResult)
+	}
+	return lastRangeKey, nil
+}
+
+// newStreamDecoder returns a decoder for a streamed response, rejecting unknown fields with
+// WithStrictDecoding
+func (c *Client) newStreamDecoder(r io.Reader) *json.Decoder {
+	dec := json.NewDecoder(r)
+	if c.strictDecoding {
+		dec.DisallowUnknownFields()
+	}
+	return dec
+}
+
+// streamPage decodes a paginated data object, handing each result to decodeResult
+func streamPage(dec *json.Decoder, decodeResult func(*json.Decoder) error) (string, error) {
+	token, err := dec.Token()
+	if err != nil {
+		return "", fmt.Errorf("failed to unmarshal data: %w", err)
+	}
+	if token == nil {
+		return "", nil
+	}
+	if delim, ok := token.(json.Delim); !ok || delim != '{' {
+		return "", fmt.Errorf("failed to unmarshal data: unexpected %v", token)
+	}
+
+	lastRangeKey := ""
+	var hasMore *bool
+	for dec.More() {
+		key, err := dec.Token()
+		if err != nil {
+			return "", fmt.Errorf("failed to unmarshal data: %w", err)
+		}
+
+		switch key {
+		case "results":
+			err = streamResults(dec, decodeResult)
+		case "lastRangeKey":
+			err = dec.Decode(&lastRangeKey)
+		case "hasMore":
+			err = dec.Decode(&hasMore)
+		default:
+			err = skipValue(dec)
+		}
+		if err != nil {
+			return "", err
+		}
+	}
+
+	if !hasNextPage(lastRangeKey, hasMore) {
+		lastRangeKey = ""
+	}
+	return lastRangeKey, expectDelim(dec, '}')
+}
+
+// streamResults calls decodeResult for each element of a results array
+func streamResults(dec *json.Decoder, decodeResult func(*json.Decoder) error) error {
+	token, err := dec.Token()
+	if err != nil {
+		return fmt.Errorf("failed to unmarshal data: %w", err)
+	}
+	if token == nil {
+		return nil
+	}
+	if delim, ok := token.(json.Delim); !ok || delim != '[' {
+		return fmt.Errorf("failed to unmarshal data: unexpected %v", token)
+	}
+
+	for dec.More() {
+		if err := decodeResult(dec); err != nil {
+			return err
+		}
+	}
+
+	return expectDelim(dec, ']')
+}
+
+// isJSONContentType reports whether a Content-Type header names JSON; a missing header is assumed to
+func isJSONContentType(contentType string) bool {
+	if contentType == "" {
+		return true
+	}
+	mediaType, _, err := mime.ParseMediaType(contentType)
+	if err != nil {
+		return false
+	}
+	return mediaType == "application/json" || strings.HasSuffix(mediaType, "+json")
+}
+
+// expectDelim reads the next token and fails unless it is delim
+func expectDelim(dec *json.Decoder, delim json.Delim) error {
+	token, err := dec.Token()
+	if err != nil {
+		return fmt.Errorf("failed to unmarshal response: %w", err)
+	}
+	if token != delim {
+		return fmt.Errorf("failed to unmarshal response: expected %v, got %v", delim, token)
+	}
+	return nil
+}
+
+// skipValue reads and discards the next value
+func skipValue(dec *json.Decoder) error {
+	var skipped json.RawMessage
+	if err := dec.Decode(&skipped); err != nil {
+		return fmt.Errorf("failed to unmarshal response: %w", err)
+	}
+	return nil
+}
+
+// maxBytesReader reads from r until remaining bytes have been read, then fails with
+// ErrResponseTooLarge if any data is left
+type maxBytesReader struct {
+	r         io.Reader
+	remaining int64
+}
+
+func (m *maxBytesReader) Read(p []byte) (int, error) {
+	if m.remaining <= 0 {
+		var probe [1]byte
+		n, err := m.r.Read(probe[:])
+		if n > 0 {
+			return 0, ErrResponseTooLarge
+		}
+		return 0, err
+	}
+
+	if int64(len(p)) > m.remaining {
+		p = p[:m.remaining]
+	}
+	n, err := m.r.Read(p)
+	m.remaining -= int64(n)
+	return n, err
+}
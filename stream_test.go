@@ -0,0 +1,53 @@
+package gdprclient
+
+import (
+	"context"
+	"errors"
+	"net/http"
+	"testing"
+)
+
+func TestStreamWaitsForEnvelopeStatus(t *testing.T) {
+	const results = `{"results":[{"partition_key":"p","range_key":"1"},{"partition_key":"p","range_key":"2"}],"lastRangeKey":"2"}`
+
+	tests := []struct {
+		name       string
+		body       string
+		wantStatus int // zero when the call should succeed
+		wantKeys   int
+	}{
+		{"status first", `{"statusCode":200,"data":` + results + `}`, 0, 2},
+		{"data first", `{"data":` + results + `,"statusCode":200}`, 0, 2},
+		{"data before error", `{"data":` + results + `,"statusCode":500,"message":"partial failure"}`, 500, 0},
+		{"data without status", `{"data":` + results + `}`, 0, 0},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			transport := roundTripFunc(func(req *http.Request) (*http.Response, error) {
+				return stubResponse(req, http.StatusOK, tt.body), nil
+			})
+			client := newStubClient(t, transport)
+
+			var seen []string
+			lastRangeKey, err := client.StreamAllInfoRequests(context.Background(), FetchAllRequestInput{PartitionKey: "p"}, func(r InfoRequest) error {
+				seen = append(seen, r.RangeKey)
+				return nil
+			})
+			if len(seen) != tt.wantKeys {
+				t.Errorf("fn saw %v, want %d results", seen, tt.wantKeys)
+			}
+
+			if tt.wantKeys > 0 {
+				if err != nil || lastRangeKey != "2" {
+					t.Errorf("StreamAllInfoRequests = %q, %v; want \"2\", nil", lastRangeKey, err)
+				}
+				return
+			}
+			var apiErr *APIError
+			if !errors.As(err, &apiErr) || apiErr.StatusCode != tt.wantStatus {
+				t.Errorf("err = %v, want an APIError with status %d", err, tt.wantStatus)
+			}
+		})
+	}
+}
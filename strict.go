@@ -0,0 +1,99 @@
+package gdprclient
+
+import (
+	"bytes"
+	"encoding/json"
+	"fmt"
+	"reflect"
+)
+
+// WithStrictDecoding makes calls fail with an error wrapping ErrUnexpectedResponse when response
+// data does not match the type it is decoded into, instead of returning a partly or wholly
+// zero-valued result. Data must be present for calls that return a record, must be a JSON object,
+// array or string as the result type requires, and must not contain fields the type lacks.
+// The response envelope itself is not checked.
+func WithStrictDecoding() ClientOption {
+	return func(c *Client) {
+		c.strictDecoding = true
+	}
+}
+
+// rawMessageType is exempt from shape checks, since it accepts any JSON value
+var rawMessageType = reflect.TypeOf(json.RawMessage{})
+
+// decodeData decodes response data into out, applying the checks of WithStrictDecoding when set.
+// Empty or null data leaves out unchanged.
+func (c *Client) decodeData(data json.RawMessage, out interface{}) error {
+	empty := len(bytes.TrimSpace(data)) == 0 || bytes.Equal(bytes.TrimSpace(data), []byte("null"))
+	if !c.strictDecoding {
+		if empty {
+			return nil
+		}
+		if err := json.Unmarshal(data, out); err != nil {
+			return fmt.Errorf("failed to unmarshal data: %v", err)
+		}
+		return nil
+	}
+
+	target := reflect.TypeOf(out)
+	if target.Kind() == reflect.Ptr {
+		target = target.Elem()
+	}
+	if empty {
+		if target == rawMessageType {
+			return nil
+		}
+		return fmt.Errorf("%w: response has no data, expected %s", ErrUnexpectedResponse, target)
+	}
+
+	if got, want := jsonKind(data), expectedJSONKind(target); want != "" && got != want {
+		return fmt.Errorf("%w: response data is %s, expected %s for %s", ErrUnexpectedResponse, got, want, target)
+	}
+
+	dec := json.NewDecoder(bytes.NewReader(data))
+	dec.DisallowUnknownFields()
+	if err := dec.Decode(out); err != nil {
+		return fmt.Errorf("%w: failed to decode data into %s: %v", ErrUnexpectedResponse, target, err)
+	}
+	return nil
+}
+
+// jsonKind names the kind of a JSON value from its first byte
+func jsonKind(data json.RawMessage) string {
+	switch bytes.TrimSpace(data)[0] {
+	case '{':
+		return "an object"
+	case '[':
+		return "an array"
+	case '"':
+		return "a string"
+	case 't', 'f':
+		return "a boolean"
+	case 'n':
+		return "null"
+	}
+	return "a number"
+}
+
+// expectedJSONKind returns the JSON kind a Go type decodes from, or "" for types that accept
+// several kinds
+func expectedJSONKind(t reflect.Type) string {
+	if t == rawMessageType {
+		return ""
+	}
+	switch t.Kind() {
+	case reflect.Struct, reflect.Map:
+		return "an object"
+	case reflect.Slice, reflect.Array:
+		return "an array"
+	case reflect.String:
+		return "a string"
+	case reflect.Bool:
+		return "a boolean"
+	case reflect.Int, reflect.Int8, reflect.Int16, reflect.Int32, reflect.Int64,
+		reflect.Uint, reflect.Uint8, reflect.Uint16, reflect.Uint32, reflect.Uint64,
+		reflect.Float32, reflect.Float64:
+		return "a number"
+	}
+	return ""
+}
@@ -0,0 +1,56 @@
+package gdprclient
+
+import (
+	"crypto/tls"
+	"crypto/x509"
+	"fmt"
+	"net/http"
+)
+
+// WithClientCertificate presents cert to the gateway for mutual TLS. TLS 1.2 is required as the
+// minimum version. It must follow any WithTransport or WithHTTPClient option, and the transport
+// must be an *http.Transport; otherwise NewClient fails.
+func WithClientCertificate(cert tls.Certificate) ClientOption {
+	return func(c *Client) {
+		c.configureTLS(func(config *tls.Config) {
+			config.Certificates = append(config.Certificates, cert)
+		})
+	}
+}
+
+// WithRootCAs verifies the gateway's certificate against pool instead of the system roots, with
+// the same requirements as WithClientCertificate
+func WithRootCAs(pool *x509.CertPool) ClientOption {
+	return func(c *Client) {
+		c.configureTLS(func(config *tls.Config) {
+			config.RootCAs = pool
+		})
+	}
+}
+
+// configureTLS applies configure to a copy of the transport's TLS config, raising the minimum
+// version to TLS 1.2. The transport is cloned so one shared with the caller is left unchanged.
+func (c *Client) configureTLS(configure func(*tls.Config)) {
+	var transport *http.Transport
+	switch t := c.httpClient.Transport.(type) {
+	case nil:
+		transport = http.DefaultTransport.(*http.Transport).Clone()
+	case *http.Transport:
+		transport = t.Clone()
+	default:
+		c.configErr = fmt.Errorf("%w: TLS options need an *http.Transport, got %T", ErrInvalidInput, t)
+		return
+	}
+
+	config := &tls.Config{}
+	if transport.TLSClientConfig != nil {
+		config = transport.TLSClientConfig.Clone()
+	}
+	if config.MinVersion < tls.VersionTLS12 {
+		config.MinVersion = tls.VersionTLS12
+	}
+	configure(config)
+
+	transport.TLSClientConfig = config
+	c.httpClient.Transport = transport
+}
@@ -0,0 +1,70 @@
+package gdprclient
+
+import (
+	"context"
+	"crypto/sha256"
+	"encoding/hex"
+)
+
+// Tracer starts spans for client calls. It is shaped so that an OpenTelemetry trace.Tracer
+// can be adapted with a few lines, without this package depending on OpenTelemetry.
+type Tracer interface {
+	Start(ctx context.Context, name string) (context.Context, Span)
+}
+
+// Span is a single traced operation started by a Tracer
+type Span interface {
+	SetAttribute(key string, value interface{})
+	End()
+}
+
+// WithTracer enables tracing. Each call gets a span named after its operation (e.g. "create" or
+// "delete/fetch") with a child span per attempt, so slow retries are visible in traces.
+func WithTracer(tracer Tracer) ClientOption {
+	return func(c *Client) {
+		c.tracer = tracer
+	}
+}
+
+// noopSpan is used when tracing is disabled
+type noopSpan struct{}
+
+func (noopSpan) SetAttribute(key string, value interface{}) {}
+
+func (noopSpan) End() {}
+
+// startSpan starts a span with the configured tracer, or a no-op span when tracing is disabled
+func (c *Client) startSpan(ctx context.Context, name string) (context.Context, Span) {
+	if c.tracer == nil {
+		return ctx, noopSpan{}
+	}
+	return c.tracer.Start(ctx, name)
+}
+
+// hashPartitionKey returns a short, stable hash of a partition key so traces do not carry the raw identifier
+func hashPartitionKey(partitionKey string) string {
+	if partitionKey == "" {
+		return ""
+	}
+	sum := sha256.Sum256([]byte(partitionKey))
+	return hex.EncodeToString(sum[:8])
+}
+
+// partitionKeyOf returns the partition key of a request input, if it has one
+func partitionKeyOf(body interface{}) string {
+	switch input := body.(type) {
+	case CreateInfoRequestInput:
+		return input.PartitionKey
+	case CreateDeleteRequestInput:
+		return input.PartitionKey
+	case FetchRequestInput:
+		return input.PartitionKey
+	case UpdateRequestInput:
+		return input.PartitionKey
+	case DeleteRequestInput:
+		return input.PartitionKey
+	case FetchAllRequestInput:
+		return input.PartitionKey
+	}
+	return ""
+}
@@ -0,0 +1,81 @@
+package gdprclient
+
+import (
+	"context"
+	"errors"
+	"net/http"
+	"testing"
+)
+
+func TestUpdateWithoutRecord(t *testing.T) {
+	tests := []struct {
+		name string
+		call func(client *Client) (interface{}, error)
+	}{
+		{"UpdateInfoRequest", func(client *Client) (interface{}, error) {
+			return client.UpdateInfoRequest(context.Background(), UpdateRequestInput{PartitionKey: "p", RangeKey: "r", Type: TypeInfoRequest})
+		}},
+		{"UpdateDeleteRequest", func(client *Client) (interface{}, error) {
+			return client.UpdateDeleteRequest(context.Background(), UpdateRequestInput{PartitionKey: "p", RangeKey: "r", Type: TypeDeleteRequest})
+		}},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			for _, body := range []string{`{"statusCode":200}`, `{"statusCode":200,"data":null}`} {
+				transport := roundTripFunc(func(req *http.Request) (*http.Response, error) {
+					return stubResponse(req, http.StatusOK, body), nil
+				})
+				client := newStubClient(t, transport)
+
+				if _, err := tt.call(client); !errors.Is(err, ErrNoRecord) {
+					t.Errorf("%s with %s: err = %v, want ErrNoRecord", tt.name, body, err)
+				}
+			}
+
+			transport := roundTripFunc(func(req *http.Request) (*http.Response, error) {
+				return stubResponse(req, http.StatusOK, `{"statusCode":200,"data":{"partition_key":"p","range_key":"r","modified":"2025-03-24T10:00:00Z"}}`), nil
+			})
+			if _, err := tt.call(newStubClient(t, transport)); err != nil {
+				t.Errorf("%s with a record: %v", tt.name, err)
+			}
+		})
+	}
+}
+
+func TestCreateConflict(t *testing.T) {
+	tests := []struct {
+		name       string
+		status     int
+		body       string
+		wantRecord bool
+	}{
+		{"with record", http.StatusConflict, `{"statusCode":409,"message":"exists","data":{"partition_key":"p","range_key":"r"}}`, true},
+		{"envelope with record", http.StatusOK, `{"statusCode":409,"message":"exists","data":{"partition_key":"p","range_key":"r"}}`, true},
+		{"without data", http.StatusConflict, `{"statusCode":409,"message":"exists"}`, false},
+		{"null data", http.StatusOK, `{"statusCode":409,"message":"exists","data":null}`, false},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			transport := roundTripFunc(func(req *http.Request) (*http.Response, error) {
+				return stubResponse(req, tt.status, tt.body), nil
+			})
+			client := newStubClient(t, transport, WithExistingOnConflict())
+
+			got, err := client.CreateInfoRequest(context.Background(), CreateInfoRequestInput{PartitionKey: "p", Type: TypeInfoRequest, CreatedBy: "test"})
+
+			var exists *AlreadyExistsError
+			if tt.wantRecord {
+				if !errors.As(err, &exists) || got == nil || got.RangeKey != "r" {
+					t.Errorf("CreateInfoRequest = %+v, %v; want the existing record and an AlreadyExistsError", got, err)
+				}
+				return
+			}
+			var apiErr *APIError
+			if got != nil || errors.As(err, &exists) || !errors.As(err, &apiErr) || !errors.Is(err, ErrConflict) {
+				t.Errorf("CreateInfoRequest = %+v, %v; want no record and an APIError matching ErrConflict", got, err)
+			}
+		})
+	}
+}
@@ -0,0 +1,173 @@
+package gdprclient
+
+import (
+	"errors"
+	"fmt"
+	"time"
+)
+
+// ErrInvalidInput is wrapped by every error returned from input validation
+var ErrInvalidInput = errors.New("invalid input")
+
+// validTypes, validStatuses and validFields are the values accepted by the service
+var (
+	validTypes    = []string{TypeInfoRequest, TypeDeleteRequest}
+	validStatuses = []string{StatusPending, StatusComplete, StatusFailed, StatusDeleted}
+	validFields   = []string{FieldPartitionKey, FieldRangeKey, FieldType, FieldStatus, FieldCreated, FieldModified, FieldCreatedBy}
+)
+
+// Validate checks that the required fields are set and Type is a known request type
+func (i CreateInfoRequestInput) Validate() error {
+	return firstError(
+		required(FieldPartitionKey, i.PartitionKey),
+		oneOf(FieldType, i.Type, validTypes, true),
+		required(FieldCreatedBy, i.CreatedBy),
+	)
+}
+
+// Validate checks that the required fields are set and Type is a known request type
+func (i CreateDeleteRequestInput) Validate() error {
+	return firstError(
+		required(FieldPartitionKey, i.PartitionKey),
+		oneOf(FieldType, i.Type, validTypes, true),
+		required(FieldCreatedBy, i.CreatedBy),
+	)
+}
+
+// Validate checks that both keys are set
+func (i FetchRequestInput) Validate() error {
+	return firstError(
+		required(FieldPartitionKey, i.PartitionKey),
+		required(FieldRangeKey, i.RangeKey),
+	)
+}
+
+// Validate checks that both keys are set and that Type and Status, if set, are known values
+func (i UpdateRequestInput) Validate() error {
+	return firstError(
+		required(FieldPartitionKey, i.PartitionKey),
+		required(FieldRangeKey, i.RangeKey),
+		oneOf(FieldType, i.Type, validTypes, false),
+		oneOf(FieldStatus, i.Status, validStatuses, false),
+	)
+}
+
+// Validate checks that both keys are set
+func (i DeleteRequestInput) Validate() error {
+	return firstError(
+		required(FieldPartitionKey, i.PartitionKey),
+		required(FieldRangeKey, i.RangeKey),
+	)
+}
+
+// Validate checks that the partition key is set and Limit and Fields are valid
+func (i FetchAllRequestInput) Validate() error {
+	return firstError(
+		required(FieldPartitionKey, i.PartitionKey),
+		pageSize(i.Limit),
+		projection(i.Fields),
+	)
+}
+
+// Validate checks that Type is a known request type and Limit and Fields are valid
+func (i FetchByTypeInput) Validate() error {
+	return firstError(
+		oneOf(FieldType, i.Type, validTypes, true),
+		pageSize(i.Limit),
+		projection(i.Fields),
+	)
+}
+
+// Validate checks that Status is a known status and Limit and Fields are valid
+func (i FetchByStatusInput) Validate() error {
+	return firstError(
+		oneOf(FieldStatus, i.Status, validStatuses, true),
+		pageSize(i.Limit),
+		projection(i.Fields),
+	)
+}
+
+// Validate checks that the creator is set and Limit and Fields are valid
+func (i FetchByCreatorInput) Validate() error {
+	return firstError(
+		required(FieldCreatedBy, i.CreatedBy),
+		pageSize(i.Limit),
+		projection(i.Fields),
+	)
+}
+
+// Validate checks that From and To are set and ordered and Limit and Fields are valid
+func (i FetchByDateRangeInput) Validate() error {
+	return firstError(
+		dateRange(i.From, i.To),
+		pageSize(i.Limit),
+		projection(i.Fields),
+	)
+}
+
+// required returns an error if value is empty
+func required(field, value string) error {
+	if value == "" {
+		return fmt.Errorf("%w: %s is required", ErrInvalidInput, field)
+	}
+	return nil
+}
+
+// dateRange returns an error if from or to is unset or to is not after from
+func dateRange(from, to time.Time) error {
+	if from.IsZero() {
+		return required("from", "")
+	}
+	if to.IsZero() {
+		return required("to", "")
+	}
+	if !to.After(from) {
+		return fmt.Errorf("%w: to must be after from, got %s to %s", ErrInvalidInput, from.Format(time.RFC3339), to.Format(time.RFC3339))
+	}
+	return nil
+}
+
+// pageSize returns an error if limit is negative or above MaxPageSize; zero means unset
+func pageSize(limit int) error {
+	if limit < 0 || limit > MaxPageSize {
+		return fmt.Errorf("%w: limit must be between 1 and %d, got %d", ErrInvalidInput, MaxPageSize, limit)
+	}
+	return nil
+}
+
+// projection returns an error if a field is not one of the Field constants
+func projection(fields []string) error {
+	for _, field := range fields {
+		if err := oneOf("fields", field, validFields, true); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+// oneOf returns an error if value is not one of allowed; an empty value is only accepted when not mandatory
+func oneOf(field, value string, allowed []string, mandatory bool) error {
+	if value == "" {
+		if mandatory {
+			return required(field, value)
+		}
+		return nil
+	}
+
+	for _, candidate := range allowed {
+		if value == candidate {
+			return nil
+		}
+	}
+	return fmt.Errorf("%w: %s must be one of %v, got %q", ErrInvalidInput, field, allowed, value)
+}
+
+// firstError returns the first non-nil error
+func firstError(errs ...error) error {
+	for _, err := range errs {
+		if err != nil {
+			return err
+		}
+	}
+	return nil
+}
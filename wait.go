@@ -0,0 +1,91 @@
+package gdprclient
+
+import (
+	"context"
+	"fmt"
+	"time"
+)
+
+// waitConfig controls how WaitForStatus polls
+type waitConfig struct {
+	interval    time.Duration
+	maxInterval time.Duration
+	factor      float64
+}
+
+// WaitOption configures WaitForStatus
+type WaitOption func(*waitConfig)
+
+// WithPollInterval sets the delay before the second poll; it grows by the poll backoff factor after each poll
+func WithPollInterval(interval time.Duration) WaitOption {
+	return func(w *waitConfig) {
+		w.interval = interval
+	}
+}
+
+// WithMaxPollInterval caps the delay between polls
+func WithMaxPollInterval(interval time.Duration) WaitOption {
+	return func(w *waitConfig) {
+		w.maxInterval = interval
+	}
+}
+
+// WithPollBackoffFactor sets the multiplier applied to the poll interval after each poll; 1 polls at a fixed rate
+func WithPollBackoffFactor(factor float64) WaitOption {
+	return func(w *waitConfig) {
+		w.factor = factor
+	}
+}
+
+// WaitForStatus polls a delete request until it reaches target, ctx is done, or target can no longer be
+// reached, so waiting for StatusDeleted carries on through StatusComplete. The last fetched request is
+// returned along with ErrRequestFailed if it ended in StatusFailed and target is another status, or an
+// error if it reached a status that cannot lead to target. Polls bypass the cache set by WithCache.
+func (c *Client) WaitForStatus(ctx context.Context, input FetchRequestInput, target string, opts ...WaitOption) (*DeleteRequest, error) {
+	config := waitConfig{
+		interval:    time.Second,
+		maxInterval: 30 * time.Second,
+		factor:      2.0,
+	}
+	for _, opt := range opts {
+		opt(&config)
+	}
+
+	// A cached record would hide the change being waited for
+	fetchCtx := ContextWithBypassCache(ctx)
+	interval := config.interval
+	for {
+		deleteRequest, err := c.FetchDeleteRequest(fetchCtx, input)
+		if err != nil {
+			return nil, err
+		}
+
+		if deleteRequest.Status == target {
+			return deleteRequest, nil
+		}
+
+		if deleteRequest.Status == StatusFailed {
+			return deleteRequest, ErrRequestFailed
+		}
+
+		if !reachable(deleteRequest.Status, target) {
+			return deleteRequest, fmt.Errorf("request reached status %s, which cannot lead to %s", deleteRequest.Status, target)
+		}
+
+		timer := c.clock.NewTimer(interval)
+		select {
+		case <-timer.C():
+		case <-ctx.Done():
+			timer.Stop()
+			return deleteRequest, ctx.Err()
+		case <-c.closed:
+			timer.Stop()
+			return deleteRequest, ErrClientClosed
+		}
+
+		interval = time.Duration(float64(interval) * config.factor)
+		if interval > config.maxInterval {
+			interval = config.maxInterval
+		}
+	}
+}
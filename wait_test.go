@@ -0,0 +1,86 @@
+package gdprclient
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"net/http"
+	"testing"
+	"time"
+)
+
+func TestWaitForStatus(t *testing.T) {
+	tests := []struct {
+		name       string
+		statuses   []string // returned by successive fetches; the last one repeats
+		target     string
+		wantStatus string
+		wantErr    error // nil for success; errAny for any error
+	}{
+		{"complete then deleted", []string{StatusPending, StatusComplete, StatusDeleted}, StatusDeleted, StatusDeleted, nil},
+		{"complete", []string{StatusPending, StatusComplete}, StatusComplete, StatusComplete, nil},
+		{"failed", []string{StatusPending, StatusFailed}, StatusDeleted, StatusFailed, ErrRequestFailed},
+		{"deleted cannot lead to complete", []string{StatusPending, StatusDeleted}, StatusComplete, StatusDeleted, errAny},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			var calls int
+			transport := roundTripFunc(func(req *http.Request) (*http.Response, error) {
+				status := tt.statuses[len(tt.statuses)-1]
+				if calls < len(tt.statuses) {
+					status = tt.statuses[calls]
+				}
+				calls++
+				return stubResponse(req, http.StatusOK, fmt.Sprintf(`{"statusCode":200,"data":{"partition_key":"p","range_key":"r","status":%q}}`, status)), nil
+			})
+			client := newStubClient(t, transport)
+
+			got, err := client.WaitForStatus(context.Background(), FetchRequestInput{PartitionKey: "p", RangeKey: "r"}, tt.target, WithPollInterval(time.Millisecond))
+			switch {
+			case tt.wantErr == nil && err != nil, tt.wantErr == errAny && err == nil:
+				t.Fatalf("err = %v, want error: %v", err, tt.wantErr != nil)
+			case tt.wantErr != nil && tt.wantErr != errAny && !errors.Is(err, tt.wantErr):
+				t.Fatalf("err = %v, want %v", err, tt.wantErr)
+			}
+			if got == nil || got.Status != tt.wantStatus {
+				t.Fatalf("WaitForStatus = %+v, want status %s", got, tt.wantStatus)
+			}
+			if calls != len(tt.statuses) {
+				t.Errorf("fetched %d times, want %d", calls, len(tt.statuses))
+			}
+		})
+	}
+}
+
+// errAny stands for any non-nil error in test tables
+var errAny = errors.New("any error")
+
+func TestWaitForStatusBypassesCache(t *testing.T) {
+	statuses := []string{StatusPending, StatusPending, StatusComplete}
+	var calls int
+	transport := roundTripFunc(func(req *http.Request) (*http.Response, error) {
+		status := statuses[len(statuses)-1]
+		if calls < len(statuses) {
+			status = statuses[calls]
+		}
+		calls++
+		return stubResponse(req, http.StatusOK, fmt.Sprintf(`{"statusCode":200,"data":{"partition_key":"p","range_key":"r","status":%q}}`, status)), nil
+	})
+	client := newStubClient(t, transport, WithCache(time.Hour))
+
+	ctx, cancel := context.WithTimeout(context.Background(), 5*time.Second)
+	defer cancel()
+
+	input := FetchRequestInput{PartitionKey: "p", RangeKey: "r"}
+	if _, err := client.FetchDeleteRequest(ctx, input); err != nil {
+		t.Fatalf("FetchDeleteRequest: %v", err)
+	}
+	got, err := client.WaitForStatus(ctx, input, StatusComplete, WithPollInterval(time.Millisecond))
+	if err != nil {
+		t.Fatalf("WaitForStatus: %v", err)
+	}
+	if got.Status != StatusComplete {
+		t.Errorf("status = %s, want %s", got.Status, StatusComplete)
+	}
+}